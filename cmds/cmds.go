@@ -12,7 +12,13 @@ import (
 )
 
 var (
-	versionFlag bool
+	versionFlag    bool
+	quarantineFlag bool
+
+	migrateSourceFlag      string
+	migrateTargetFlag      string
+	migrateSpaceIdsFlag    []string
+	migrateConcurrencyFlag int
 )
 
 var root = &cobra.Command{
@@ -42,8 +48,71 @@ var start = &cobra.Command{
 	},
 }
 
+var scrub = &cobra.Command{
+	Use:   "scrub",
+	Short: "Command to verify the digest of all stored resources",
+	Long:  "Command to verify the digest of all stored resources, resumes from the last checkpoint if exists",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := server.Scrub(quarantineFlag); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "scrub fail: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var migrate = &cobra.Command{
+	Use:   "migrate",
+	Short: "Command to migrate resources between storage backends",
+	Long:  "Command to copy and verify resources from source storage backend to target, the copied files are skipped when run again",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := server.Migrate(migrateSourceFlag, migrateTargetFlag, migrateSpaceIdsFlag, migrateConcurrencyFlag)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "migrate fail: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Commands to manage config",
+	Long:  "Commands to manage config",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help()
+	},
+}
+
+var validateConfig = &cobra.Command{
+	Use:   "validate",
+	Short: "Command to validate config",
+	Long:  "Command to validate config file and env strictly, includes the unknown keys in file and the requirements of storage backends",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config.Strict = true
+		// The missing file is skipped when loading, but it must exist to be validated.
+		if config.FilePath != "" {
+			if _, err := os.Stat(config.FilePath); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := config.Check(); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("config is valid")
+	},
+}
+
 func Execute() {
 	root.AddCommand(start)
+	root.AddCommand(scrub)
+	root.AddCommand(migrate)
+	configCmd.AddCommand(validateConfig)
+	root.AddCommand(configCmd)
 
 	if err := root.Execute(); err != nil {
 		os.Exit(1)
@@ -60,4 +129,37 @@ func init() {
 	start.Flags().StringVarP(
 		&config.FilePath, "config", "c", "", "path of config file",
 	)
+	start.Flags().BoolVar(
+		&config.Strict, "strict", false, "reject the unknown keys in config file, they are only warned if false",
+	)
+
+	// set scrub command flags
+	scrub.Flags().StringVarP(
+		&config.FilePath, "config", "c", "", "path of config file",
+	)
+	scrub.Flags().BoolVarP(
+		&quarantineFlag, "quarantine", "q", false, "move the mismatched files to quarantine directory",
+	)
+
+	// set config validate command flags
+	validateConfig.Flags().StringVarP(
+		&config.FilePath, "config", "c", "", "path of config file",
+	)
+
+	// set migrate command flags
+	migrate.Flags().StringVarP(
+		&config.FilePath, "config", "c", "", "path of config file",
+	)
+	migrate.Flags().StringVarP(
+		&migrateSourceFlag, "source", "s", config.DefaultStorageName, "name of storage backend to copy from",
+	)
+	migrate.Flags().StringVarP(
+		&migrateTargetFlag, "target", "t", "", "name of storage backend to copy to",
+	)
+	migrate.Flags().StringSliceVar(
+		&migrateSpaceIdsFlag, "space-ids", nil, "the workspaces to migrate, all workspaces if empty",
+	)
+	migrate.Flags().IntVarP(
+		&migrateConcurrencyFlag, "concurrency", "n", 0, "max number of files to copy concurrently, use the config if 0",
+	)
 }
@@ -1,9 +1,14 @@
 package config
 
 import (
-	"errors"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/DataWorkbench/common/grpcwrap"
@@ -21,13 +26,26 @@ import (
 // FilePath The config file path used by Load config
 var FilePath string
 
+// Strict reports whether to reject the unknown keys in config file, such as the misspelled section.
+// The unknown keys are only warned if it is false.
+var Strict = false
+
 const (
 	envPrefix = "RESOURCE_MANAGER"
 )
 
 const (
-	StorageBackgroundHDFS = "hdfs"
-	StorageBackgroundS3   = "s3"
+	// HadoopProxyUserWorkspace means access HDFS as the user named by workspace id.
+	HadoopProxyUserWorkspace = "workspace"
+	// HadoopProxyUserCaller means access HDFS as the user specified by caller in gRPC metadata.
+	HadoopProxyUserCaller = "caller"
+)
+
+const (
+	StorageBackgroundHDFS   = "hdfs"
+	StorageBackgroundS3     = "s3"
+	StorageBackgroundLocal  = "local"
+	StorageBackgroundMemory = "memory"
 )
 
 type Storage struct {
@@ -35,6 +53,148 @@ type Storage struct {
 	Background    string           `json:"background" yaml:"background" env:"BACKGROUND,default=hdfs" validate:"required"`
 	HadoopConfDir string           `json:"hadoop_conf_dir" yaml:"hadoop_conf_dir" env:"HADOOP_CONF_DIR" validate:"-"`
 	S3            *fileio.S3Config `json:"s3" yaml:"s3" env:"S3" validate:"-"`
+
+	// The default user to access HDFS. Use root if empty,
+	// or the kerberos principal if kerberos is enabled.
+	HadoopUser string `json:"hadoop_user" yaml:"hadoop_user" env:"HADOOP_USER" validate:"-"`
+	// The strategy to impersonate proxy user for each request. Disabled if empty.
+	// Supported value: "workspace", "caller".
+	HadoopProxyUser string `json:"hadoop_proxy_user" yaml:"hadoop_proxy_user" env:"HADOOP_PROXY_USER" validate:"omitempty,oneof=workspace caller"`
+	// The regular expression that the user specified by caller must match entirely, the others are
+	// rejected. Is required if hadoop_proxy_user is "caller", the metadata is not authenticated.
+	HadoopProxyUserPattern string `json:"hadoop_proxy_user_pattern" yaml:"hadoop_proxy_user_pattern" env:"HADOOP_PROXY_USER_PATTERN" validate:"required_if=HadoopProxyUser caller"`
+	// The prefix of proxy user name.
+	HadoopProxyUserPrefix string `json:"hadoop_proxy_user_prefix" yaml:"hadoop_proxy_user_prefix" env:"HADOOP_PROXY_USER_PREFIX" validate:"-"`
+	// The max number of HDFS clients for different users.
+	HadoopClientPoolSize int `json:"hadoop_client_pool_size" yaml:"hadoop_client_pool_size" env:"HADOOP_CLIENT_POOL_SIZE,default=16" validate:"gte=1"`
+	// The kerberos config for HDFS. Is required if kerberos is enabled in hadoop config.
+	Kerberos *fileio.KerberosConfig `json:"kerberos" yaml:"kerberos" env:"KERBEROS" validate:"-"`
+
+	// The staging files that not modified within the duration will be removed at startup.
+	StagingExpire time.Duration `json:"staging_expire" yaml:"staging_expire" env:"STAGING_EXPIRE,default=24h" validate:"gt=0"`
+	// The upload session will be expired if no chunk uploaded within the duration.
+	UploadSessionTTL time.Duration `json:"upload_session_ttl" yaml:"upload_session_ttl" env:"UPLOAD_SESSION_TTL,default=24h" validate:"gt=0"`
+
+	// The max number of resource files to delete concurrently in bulk delete.
+	DeleteConcurrency int `json:"delete_concurrency" yaml:"delete_concurrency" env:"DELETE_CONCURRENCY,default=8" validate:"gte=1"`
+	// The max number of files to copy concurrently in migration between storage backends.
+	MigrateConcurrency int `json:"migrate_concurrency" yaml:"migrate_concurrency" env:"MIGRATE_CONCURRENCY,default=4" validate:"gte=1"`
+
+	// The root directory for store resources in local filesystem.
+	LocalRootDir string `json:"local_root_dir" yaml:"local_root_dir" env:"LOCAL_ROOT_DIR" validate:"-"`
+	// The max bytes of data can be stored in memory, 0 means unlimited.
+	MemoryCapacity int64 `json:"memory_capacity" yaml:"memory_capacity" env:"MEMORY_CAPACITY,default=0" validate:"gte=0"`
+}
+
+// DefaultStorageName is the name of storage backend that configured by `storage`.
+const DefaultStorageName = "default"
+
+// storageNameRegexp limits the name of storage backend, the name is used in env prefix.
+var storageNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// Routing is the rules to choose storage backend for workspaces.
+type Routing struct {
+	// The storage backend name for workspaces that not in mapping.
+	Default string `json:"default" yaml:"default" env:"DEFAULT,default=default" validate:"required"`
+	// The storage backend name for specified workspaces, the key is space id.
+	// Format in env: "wks-0000000000000001:hdfs2 wks-0000000000000002:s3".
+	Workspaces map[string]string `json:"workspaces" yaml:"workspaces" env:"WORKSPACES" validate:"-"`
+	// The reads of a storage backend fall back to another backend if file not exists, the key is
+	// backend name and the value is the backend to fall back to. It is used to serve the workspaces
+	// that are being migrated. Format in env: "s3:default".
+	Fallbacks map[string]string `json:"fallbacks" yaml:"fallbacks" env:"FALLBACKS" validate:"-"`
+}
+
+// ReadCache is the config of local disk cache for the resource files that read by ReadFileData.
+type ReadCache struct {
+	// Whether to cache the resource files on local disk.
+	Enabled bool `json:"enabled" yaml:"enabled" env:"ENABLED,default=false" validate:"-"`
+	// The directory to store cached files, each storage backend uses a sub directory named by backend name.
+	Dir string `json:"dir" yaml:"dir" env:"DIR,default=/tmp/resourcemanager/cache" validate:"required"`
+	// The max MiB of cached files for each storage backend, the least recently used files are evicted.
+	Capacity int64 `json:"capacity" yaml:"capacity" env:"CAPACITY,default=10240" validate:"gte=1"`
+	// The interval to check whether a cached file is modified in storage, the reads within it are
+	// served from cache without accessing storage. 0 means check on every read.
+	RevalidateInterval time.Duration `json:"revalidate_interval" yaml:"revalidate_interval" env:"REVALIDATE_INTERVAL,default=1m" validate:"gte=0"`
+}
+
+// Scrubber is the config of integrity scrubber that re-hashes the stored resources.
+type Scrubber struct {
+	// Whether to run the scrubber continuously in server.
+	Enabled bool `json:"enabled" yaml:"enabled" env:"ENABLED,default=false" validate:"-"`
+	// The duration to wait between two scrub passes.
+	Interval time.Duration `json:"interval" yaml:"interval" env:"INTERVAL,default=24h" validate:"gt=0"`
+	// The max MiB of data to read per second, 0 means unlimited.
+	RateLimit int64 `json:"rate_limit" yaml:"rate_limit" env:"RATE_LIMIT,default=16" validate:"gte=0"`
+	// The interval to save the progress, the scrubber resumes from the last checkpoint after restart.
+	CheckpointInterval time.Duration `json:"checkpoint_interval" yaml:"checkpoint_interval" env:"CHECKPOINT_INTERVAL,default=1m" validate:"gt=0"`
+	// The instances that share the storage take turns to scrub by a lease, it is renewed on each checkpoint
+	// and taken over by others after expired. It must be longer than the time to verify the largest file.
+	LeaseTTL time.Duration `json:"lease_ttl" yaml:"lease_ttl" env:"LEASE_TTL,default=10m" validate:"gtfield=CheckpointInterval"`
+	// Whether to move the mismatched files to quarantine directory, or only report them.
+	Quarantine bool `json:"quarantine" yaml:"quarantine" env:"QUARANTINE,default=false" validate:"-"`
+}
+
+// Health is the config of the gRPC health service that reflects the storage connectivity.
+// The health service on the address of `grpc_server` is registered by grpcwrap and always
+// SERVING, it only tells the process is alive. The readiness probe must check the health
+// server on Address, which is served separately for that reason.
+type Health struct {
+	// Listening address of the health server, it must be reachable by the readiness probe.
+	Address string `json:"address" yaml:"address" env:"ADDRESS,default=:9131" validate:"required"`
+	// The interval to probe all storage backends.
+	ProbeInterval time.Duration `json:"probe_interval" yaml:"probe_interval" env:"PROBE_INTERVAL,default=10s" validate:"gt=0"`
+	// The probe that not finished within timeout is failed.
+	ProbeTimeout time.Duration `json:"probe_timeout" yaml:"probe_timeout" env:"PROBE_TIMEOUT,default=5s" validate:"gt=0"`
+	// The status flips to NOT_SERVING after the number of consecutive failed probes of any backend.
+	FailureThreshold int `json:"failure_threshold" yaml:"failure_threshold" env:"FAILURE_THRESHOLD,default=3" validate:"gte=1"`
+}
+
+// HTTPGateway is the config of the HTTP server that serves the upload and download of resource
+// files at /{spaceId}/{fileId}/{version}, for the clients that can't use gRPC such as web browsers.
+type HTTPGateway struct {
+	Enabled bool `json:"enabled" yaml:"enabled" env:"ENABLED,default=false" validate:"-"`
+	// Listening address of the HTTP gateway.
+	Address string `json:"address" yaml:"address" env:"ADDRESS,default=127.0.0.1:9132" validate:"required_with=Enabled"`
+	// The max MiB of uploaded file, 0 means unlimited.
+	MaxUploadSize int64 `json:"max_upload_size" yaml:"max_upload_size" env:"MAX_UPLOAD_SIZE,default=0" validate:"gte=0"`
+	// The key to verify the signed URL, the services that issue the URL to web pages sign it with the same key.
+	SigningKey string `json:"signing_key" yaml:"signing_key" env:"SIGNING_KEY" validate:"required_with=Enabled" secret:"true"`
+	// The origins of web pages that can access by CORS, "*" means any origin. CORS is disabled if empty.
+	CORSAllowedOrigins []string `json:"cors_allowed_origins" yaml:"cors_allowed_origins" env:"CORS_ALLOWED_ORIGINS" validate:"-"`
+	// The time to read the request headers. The body is not limited, because the upload may take long.
+	ReadHeaderTimeout time.Duration `json:"read_header_timeout" yaml:"read_header_timeout" env:"READ_HEADER_TIMEOUT,default=10s" validate:"gt=0"`
+	// The time to keep the idle connection.
+	IdleTimeout time.Duration `json:"idle_timeout" yaml:"idle_timeout" env:"IDLE_TIMEOUT,default=60s" validate:"gt=0"`
+}
+
+// Reload is the config of reloading configuration at runtime. The configuration is reloaded
+// on SIGHUP or when the config file modified, only the changes that safe to apply are applied
+// live, such as log level, storage credentials, cache capacity and rate limits.
+type Reload struct {
+	// The interval to check whether the config file modified.
+	WatchInterval time.Duration `json:"watch_interval" yaml:"watch_interval" env:"WATCH_INTERVAL,default=10s" validate:"gt=0"`
+}
+
+// Shutdown is the config of the graceful shutdown. The server stops accepting new transfers
+// and waits the transfers in flight to finish, the ones still running at the deadline are
+// cancelled and their partial files are cleaned up.
+type Shutdown struct {
+	// The max time to wait the transfers in flight to finish.
+	DrainTimeout time.Duration `json:"drain_timeout" yaml:"drain_timeout" env:"DRAIN_TIMEOUT,default=60s" validate:"gt=0"`
+	// The interval to log the transfers in flight during the drain.
+	LogInterval time.Duration `json:"log_interval" yaml:"log_interval" env:"LOG_INTERVAL,default=10s" validate:"gt=0"`
+}
+
+// Trash is the config of trash that keeps the deleted resources for a while.
+type Trash struct {
+	// Whether to move the deleted resources to trash instead of removing them immediately.
+	// The HDFS trash (fs.trash.interval) is not used, because it is only supported by hadoop shell.
+	Enabled bool `json:"enabled" yaml:"enabled" env:"ENABLED,default=false" validate:"-"`
+	// The entries in trash will be purged after the retention.
+	Retention time.Duration `json:"retention" yaml:"retention" env:"RETENTION,default=168h" validate:"gt=0"`
+	// The interval to purge the expired entries.
+	PurgeInterval time.Duration `json:"purge_interval" yaml:"purge_interval" env:"PURGE_INTERVAL,default=1h" validate:"gt=0"`
 }
 
 // Config is the configuration settings for spacemanager
@@ -45,9 +205,28 @@ type Config struct {
 	GRPCLog       *grpcwrap.LogConfig    `json:"grpc_log"       yaml:"grpc_log"       env:"GRPC_LOG"            validate:"required"`
 	MetricsServer *metrics.Config        `json:"metrics_server" yaml:"metrics_server" env:"METRICS_SERVER"      validate:"required"`
 	Tracer        *gtrace.Config         `json:"tracer"         yaml:"tracer"         env:"TRACER"              validate:"required"`
+	Health        *Health                `json:"health"         yaml:"health"         env:"HEALTH"              validate:"required"`
+	HTTPGateway   *HTTPGateway           `json:"http_gateway"   yaml:"http_gateway"   env:"HTTP_GATEWAY"        validate:"required"`
 
 	Storage *Storage `json:"storage" yaml:"storage" env:"STORAGE" validate:"required"`
 
+	// The additional named storage backends, the key is backend name. Only the fields to connect
+	// storage are used, the others are shared from `storage`. The fields can be set in env with
+	// prefix `RESOURCE_MANAGER_STORAGES_<NAME>_`, but the backend must be declared in file.
+	Storages map[string]*Storage `json:"storages" yaml:"storages" env:"-" validate:"-"`
+
+	Routing *Routing `json:"routing" yaml:"routing" env:"ROUTING" validate:"required"`
+
+	ReadCache *ReadCache `json:"read_cache" yaml:"read_cache" env:"READ_CACHE" validate:"required"`
+
+	Scrubber *Scrubber `json:"scrubber" yaml:"scrubber" env:"SCRUBBER" validate:"required"`
+
+	Trash *Trash `json:"trash" yaml:"trash" env:"TRASH" validate:"required"`
+
+	Reload *Reload `json:"reload" yaml:"reload" env:"RELOAD" validate:"required"`
+
+	Shutdown *Shutdown `json:"shutdown" yaml:"shutdown" env:"SHUTDOWN" validate:"required"`
+
 	//// storage_background
 	//StorageBackground string           `json:"storage_background" yaml:"storage_background" env:"STORAGE_BACKGROUND,default=hdfs" validate:"required"`
 	//HadoopConfDir     string           `json:"hadoop_conf_dir" yaml:"hadoop_conf_dir" env:"HADOOP_CONF_DIR" validate:"-"`
@@ -64,22 +243,72 @@ func loadFromFile(cfg *Config) (err error) {
 	var b []byte
 	b, err = envsubst.ReadFile(FilePath)
 	if err != nil && os.IsNotExist(err) {
-		return
+		// Such as the default path in image, the config is loaded from env only.
+		fmt.Printf("%s config file <%s> not exists, skip it\n", time.Now().Format(time.RFC3339Nano), FilePath)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read config file <%s>: %w", FilePath, err)
 	}
 
-	err = yaml.Unmarshal(b, cfg)
+	// The error reports all unknown keys with line numbers, they are only warned if not strict.
+	if err = decodeYAML(b, cfg, true); err != nil && !Strict {
+		*cfg = Config{}
+		if decodeYAML(b, cfg, false) == nil {
+			fmt.Printf("%s WARNING: config file <%s>: %v\n", time.Now().Format(time.RFC3339Nano), FilePath, err)
+			return nil
+		}
+	}
 	if err != nil {
-		fmt.Println("parse config file error:", err)
+		return fmt.Errorf("parse config file <%s>: %w", FilePath, err)
 	}
-	return
+	return nil
+}
+
+// decodeYAML decodes the config file into cfg, the unknown keys are rejected if knownFields.
+func decodeYAML(b []byte, cfg *Config, knownFields bool) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(knownFields)
+	if err := decoder.Decode(cfg); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
 }
 
 // Load load all configuration from specified file
 // Must be set `FilePath` before called
 func Load() (cfg *Config, err error) {
-	cfg = &Config{}
+	if cfg, err = parse(); err != nil {
+		return
+	}
+
+	// output the config content, the secrets are masked.
+	fmt.Printf("%s pid=%d the latest configuration: \n", time.Now().Format(time.RFC3339Nano), os.Getpid())
+	fmt.Println("")
+	if redacted, err := cfg.Redacted(); err == nil {
+		b, _ := yaml.Marshal(redacted)
+		fmt.Println(string(b))
+	}
 
-	_ = loadFromFile(cfg)
+	err = cfg.Validate()
+	return
+}
+
+// Check loads the configuration same as Load without output, and returns the first problem found.
+func Check() error {
+	cfg, err := parse()
+	if err != nil {
+		return err
+	}
+	return cfg.Validate()
+}
+
+// parse loads the configuration from file and env.
+func parse() (cfg *Config, err error) {
+	cfg = &Config{}
+	if err = loadFromFile(cfg); err != nil {
+		return
+	}
 
 	l := loader.New(
 		loader.WithPrefix(envPrefix),
@@ -89,31 +318,165 @@ func Load() (cfg *Config, err error) {
 	if err = l.Load(cfg); err != nil {
 		return
 	}
+	if err = loadStorages(cfg); err != nil {
+		return
+	}
+	err = resolveSecretFiles(reflect.ValueOf(cfg), "")
+	return
+}
 
-	// output the config content
-	fmt.Printf("%s pid=%d the latest configuration: \n", time.Now().Format(time.RFC3339Nano), os.Getpid())
-	fmt.Println("")
-	b, _ := yaml.Marshal(cfg)
-	fmt.Println(string(b))
-
+// Validate checks the configuration, includes the requirements of each storage backend.
+func (cfg *Config) Validate() (err error) {
 	validate := validator.New()
 	if err = validate.Struct(cfg); err != nil {
 		return
 	}
 
-	// check storage background
-	switch cfg.Storage.Background {
+	if err = validateStorage(validate, DefaultStorageName, cfg.Storage); err != nil {
+		return
+	}
+	for _, name := range cfg.StorageNames() {
+		if name == DefaultStorageName {
+			continue
+		}
+		if err = validate.Struct(cfg.Storages[name]); err != nil {
+			return
+		}
+		if err = validateStorage(validate, name, cfg.Storages[name]); err != nil {
+			return
+		}
+	}
+	err = validateRouting(cfg)
+	return
+}
+
+// loadStorages applies the env and default values to the named storage backends.
+func loadStorages(cfg *Config) error {
+	for name, st := range cfg.Storages {
+		if !storageNameRegexp.MatchString(name) {
+			return fmt.Errorf("invalid storage name <%s>, must match %s", name, storageNameRegexp.String())
+		}
+		if name == DefaultStorageName {
+			return fmt.Errorf("storage name <%s> is reserved", name)
+		}
+		if st == nil {
+			st = &Storage{}
+			cfg.Storages[name] = st
+		}
+		l := loader.New(
+			loader.WithPrefix(envPrefix+"_STORAGES_"+strings.ToUpper(name)),
+			loader.WithTagName("env"),
+			loader.WithOverride(true),
+		)
+		if err := l.Load(st); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StorageNames returns the names of all storage backends in order, includes the default.
+func (cfg *Config) StorageNames() []string {
+	names := make([]string, 0, len(cfg.Storages)+1)
+	names = append(names, DefaultStorageName)
+	for name := range cfg.Storages {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// GetStorage returns the storage backend config by name. Returns nil if not exists.
+func (cfg *Config) GetStorage(name string) *Storage {
+	if name == DefaultStorageName {
+		return cfg.Storage
+	}
+	return cfg.Storages[name]
+}
+
+// validateStorage checks the fields that required by storage background.
+func validateStorage(validate *validator.Validate, name string, st *Storage) (err error) {
+	if st.HadoopProxyUserPattern != "" {
+		if _, err = regexp.Compile(st.HadoopProxyUserPattern); err != nil {
+			return fmt.Errorf("storage <%s>: hadoop_proxy_user_pattern: %w", name, err)
+		}
+	}
+	switch st.Background {
 	case StorageBackgroundHDFS:
-		if cfg.Storage.HadoopConfDir == "" {
-			err = errors.New("hadoop_conf_dir must specified when storage_background is hdfs")
+		if st.HadoopConfDir == "" {
+			err = fmt.Errorf("storage <%s>: hadoop_conf_dir must specified when storage_background is hdfs", name)
+			break
+		}
+		if err = checkPath(name, "hadoop_conf_dir", st.HadoopConfDir, true); err != nil {
+			break
+		}
+		if krb := st.Kerberos; krb != nil && (krb.KeytabPath != "" || krb.CCachePath != "") {
+			if err = validate.Struct(krb); err != nil {
+				break
+			}
+			if err = checkPath(name, "kerberos.keytab_path", krb.KeytabPath, false); err != nil {
+				break
+			}
+			if err = checkPath(name, "kerberos.ccache_path", krb.CCachePath, false); err != nil {
+				break
+			}
+			err = checkPath(name, "kerberos.krb5_conf_path", krb.Krb5ConfPath, false)
 		}
 	case StorageBackgroundS3:
-		err = validate.Struct(cfg.Storage.S3)
+		if err = validate.Struct(st.S3); err != nil {
+			break
+		}
+		if err = checkPath(name, "s3.access_key_id_file", st.S3.AccessKeyIdFile, false); err != nil {
+			break
+		}
+		err = checkPath(name, "s3.secret_access_key_file", st.S3.SecretAccessKeyFile, false)
+	case StorageBackgroundLocal:
+		if st.LocalRootDir == "" {
+			err = fmt.Errorf("storage <%s>: local_root_dir must specified when storage_background is local", name)
+		}
+	case StorageBackgroundMemory:
 	default:
-		err = errors.New("unsupported storage background")
+		err = fmt.Errorf("storage <%s>: unsupported storage background", name)
 	}
+	return
+}
+
+// checkPath checks the file or directory of the storage field exists if path is not empty.
+func checkPath(name string, field string, path string, isDir bool) error {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
 	if err != nil {
-		return
+		return fmt.Errorf("storage <%s>: %s: %w", name, field, err)
 	}
-	return
+	if info.IsDir() != isDir {
+		kind := "a file"
+		if isDir {
+			kind = "a directory"
+		}
+		return fmt.Errorf("storage <%s>: %s <%s> is not %s", name, field, path, kind)
+	}
+	return nil
+}
+
+// validateRouting checks that routing rules refer to the declared storage backends.
+func validateRouting(cfg *Config) error {
+	if cfg.GetStorage(cfg.Routing.Default) == nil {
+		return fmt.Errorf("routing: default storage <%s> not declared", cfg.Routing.Default)
+	}
+	for spaceId, name := range cfg.Routing.Workspaces {
+		if cfg.GetStorage(name) == nil {
+			return fmt.Errorf("routing: storage <%s> of workspace <%s> not declared", name, spaceId)
+		}
+	}
+	for name, fallback := range cfg.Routing.Fallbacks {
+		if cfg.GetStorage(name) == nil || cfg.GetStorage(fallback) == nil {
+			return fmt.Errorf("routing: fallback <%s:%s> refers to undeclared storage", name, fallback)
+		}
+		if name == fallback {
+			return fmt.Errorf("routing: storage <%s> can not fall back to itself", name)
+		}
+	}
+	return nil
 }
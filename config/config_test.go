@@ -0,0 +1,165 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setConfigFile writes data to a temporary config file and sets FilePath and Strict,
+// they are restored after test.
+func setConfigFile(t *testing.T, data string, strict bool) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	filePath, oldStrict := FilePath, Strict
+	t.Cleanup(func() { FilePath, Strict = filePath, oldStrict })
+	FilePath, Strict = file, strict
+}
+
+func TestLoadFromFileStrict(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		strict bool
+		err    string
+	}{
+		{
+			name:   "known keys",
+			data:   "storage:\n  background: memory\n",
+			strict: true,
+		},
+		{
+			name: "empty",
+			data: "",
+		},
+		{
+			name:   "unknown section",
+			data:   "storage:\n  background: memory\nstorgae:\n  background: s3\n",
+			strict: true,
+			err:    "line 3: field storgae not found",
+		},
+		{
+			name:   "unknown nested key",
+			data:   "storage:\n  backgroud: memory\n",
+			strict: true,
+			err:    "line 2: field backgroud not found",
+		},
+		{
+			name:   "unknown key not strict",
+			data:   "storage:\n  background: memory\nstorgae:\n  background: s3\n",
+			strict: false,
+		},
+		{
+			name: "invalid not strict",
+			data: "storage: [memory\n",
+			err:  "parse config file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfigFile(t, tt.data, tt.strict)
+			cfg := &Config{}
+			err := loadFromFile(cfg)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if tt.data != "" && (cfg.Storage == nil || cfg.Storage.Background != StorageBackgroundMemory) {
+				t.Fatalf("storage = %+v", cfg.Storage)
+			}
+		})
+	}
+}
+
+func TestLoadFromFileNotExist(t *testing.T) {
+	filePath := FilePath
+	t.Cleanup(func() { FilePath = filePath })
+	FilePath = filepath.Join(t.TempDir(), "config.yaml")
+	cfg := &Config{}
+	if err := loadFromFile(cfg); err != nil || cfg.Storage != nil {
+		t.Fatalf("load: %v, storage = %+v", err, cfg.Storage)
+	}
+}
+
+func TestParseStorageNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		names []string
+		err   string
+	}{
+		{
+			name:  "default only",
+			data:  "storage:\n  background: memory\n",
+			names: []string{DefaultStorageName},
+		},
+		{
+			name:  "named",
+			data:  "storages:\n  hot:\n    background: memory\n  archive:\n",
+			names: []string{DefaultStorageName, "archive", "hot"},
+		},
+		{
+			name: "invalid name",
+			data: "storages:\n  Hot:\n    background: memory\n",
+			err:  "invalid storage name <Hot>",
+		},
+		{
+			name: "reserved name",
+			data: "storages:\n  default:\n    background: memory\n",
+			err:  "storage name <default> is reserved",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setConfigFile(t, tt.data, true)
+			cfg, err := parse()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if got := strings.Join(cfg.StorageNames(), ","); got != strings.Join(tt.names, ",") {
+				t.Fatalf("names = %s, want %v", got, tt.names)
+			}
+			// The default values are applied to the named storages.
+			for _, name := range tt.names {
+				if st := cfg.GetStorage(name); st == nil || st.Background == "" {
+					t.Fatalf("storage %s = %+v", name, st)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSampleConfig(t *testing.T) {
+	// The paths in sample config are relative to the repository root.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err = os.Chdir(".."); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	filePath, strict := FilePath, Strict
+	t.Cleanup(func() {
+		FilePath, Strict = filePath, strict
+		_ = os.Chdir(wd)
+	})
+	FilePath, Strict = "config/config.yaml", true
+	if err := Check(); err != nil {
+		t.Fatalf("check config.yaml: %v", err)
+	}
+}
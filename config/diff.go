@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Diff returns the paths of the fields that differ between old and new in order, such as
+// "storage.s3.bucket" or "storages.archive" if the backend is added or removed. The path
+// is joined by the yaml names of fields and the keys of maps.
+func Diff(old, new *Config) []string {
+	var paths []string
+	diffValue("", reflect.ValueOf(old), reflect.ValueOf(new), &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func diffValue(path string, a, b reflect.Value, paths *[]string) {
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				*paths = append(*paths, path)
+			}
+			return
+		}
+		diffValue(path, a.Elem(), b.Elem(), paths)
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < a.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				// Unexported.
+				continue
+			}
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			diffValue(joinPath(path, name), a.Field(i), b.Field(i), paths)
+		}
+	case reflect.Map:
+		for _, key := range a.MapKeys() {
+			sub := joinPath(path, fmt.Sprint(key.Interface()))
+			if bv := b.MapIndex(key); bv.IsValid() {
+				diffValue(sub, a.MapIndex(key), bv, paths)
+			} else {
+				*paths = append(*paths, sub)
+			}
+		}
+		for _, key := range b.MapKeys() {
+			if !a.MapIndex(key).IsValid() {
+				*paths = append(*paths, joinPath(path, fmt.Sprint(key.Interface())))
+			}
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*paths = append(*paths, path)
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
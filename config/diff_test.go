@@ -0,0 +1,77 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataWorkbench/common/utils/logutil"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+)
+
+func TestDiff(t *testing.T) {
+	newConfig := func() *Config {
+		return &Config{
+			LogConfig: &logutil.Config{Level: 1},
+			Storage: &Storage{
+				Background: StorageBackgroundS3,
+				S3:         &fileio.S3Config{Bucket: "bucket", AccessKeyId: "id"},
+			},
+			Storages:  map[string]*Storage{"archive": {Background: StorageBackgroundMemory}},
+			ReadCache: &ReadCache{},
+			Trash:     &Trash{Retention: time.Hour},
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		paths  []string
+	}{
+		{
+			name:   "same",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "fields",
+			modify: func(cfg *Config) {
+				cfg.LogConfig.Level = 2
+				cfg.Storage.S3.AccessKeyId = "rotated"
+				cfg.Trash.Retention = time.Minute
+			},
+			paths: []string{"log.level", "storage.s3.access_key_id", "trash.retention"},
+		},
+		{
+			name: "pointer set",
+			modify: func(cfg *Config) {
+				cfg.Storage.S3 = nil
+				cfg.Scrubber = &Scrubber{}
+			},
+			paths: []string{"scrubber", "storage.s3"},
+		},
+		{
+			name: "storage added and removed",
+			modify: func(cfg *Config) {
+				delete(cfg.Storages, "archive")
+				cfg.Storages["hot"] = &Storage{Background: StorageBackgroundMemory}
+			},
+			paths: []string{"storages.archive", "storages.hot"},
+		},
+		{
+			name: "storage modified",
+			modify: func(cfg *Config) {
+				cfg.Storages["archive"].Background = StorageBackgroundLocal
+			},
+			paths: []string{"storages.archive.background"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			tt.modify(cfg)
+			got := strings.Join(Diff(newConfig(), cfg), ",")
+			if want := strings.Join(tt.paths, ","); got != want {
+				t.Fatalf("diff = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue replaces the value of secret fields in the config dump.
+const redactedValue = "******"
+
+// secretFilePrefix marks the value of secret field as a reference to the file that contains
+// the secret, such as `file:/var/run/secrets/s3/access_key_id`. It is read at each load, so
+// the rotated secret takes effect on reload.
+const secretFilePrefix = "file:"
+
+// Redacted returns a copy of config that the string fields tagged with `secret:"true"`
+// are masked, it is safe to print to logs.
+func (cfg *Config) Redacted() (*Config, error) {
+	b, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	out := &Config{}
+	if err = yaml.Unmarshal(b, out); err != nil {
+		return nil, err
+	}
+	redactSecrets(reflect.ValueOf(out))
+	return out, nil
+}
+
+// redactSecrets masks the non-empty secret fields in v recursively.
+func redactSecrets(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			redactSecrets(v.Elem())
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				// Unexported.
+				continue
+			}
+			fv := v.Field(i)
+			if field.Tag.Get("secret") == "true" && fv.Kind() == reflect.String {
+				if fv.String() != "" {
+					fv.SetString(redactedValue)
+				}
+				continue
+			}
+			redactSecrets(fv)
+		}
+	case reflect.Map:
+		// Only the pointer values can be modified in place.
+		for _, key := range v.MapKeys() {
+			redactSecrets(v.MapIndex(key))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			redactSecrets(v.Index(i))
+		}
+	}
+}
+
+// resolveSecretFiles replaces the value of string fields tagged with `secret:"true"` that
+// starts with secretFilePrefix by the content of file, with the surrounding whitespace trimmed.
+func resolveSecretFiles(v reflect.Value, name string) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return resolveSecretFiles(v.Elem(), name)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				// Unexported.
+				continue
+			}
+			fieldName := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if name != "" {
+				fieldName = name + "." + fieldName
+			}
+			fv := v.Field(i)
+			if field.Tag.Get("secret") == "true" && fv.Kind() == reflect.String {
+				file := strings.TrimPrefix(fv.String(), secretFilePrefix)
+				if file == fv.String() {
+					continue
+				}
+				b, err := os.ReadFile(file)
+				if err != nil {
+					return fmt.Errorf("read secret file of <%s>: %w", fieldName, err)
+				}
+				fv.SetString(strings.TrimSpace(string(b)))
+				continue
+			}
+			if err := resolveSecretFiles(fv, fieldName); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// Only the pointer values can be modified in place.
+		for _, key := range v.MapKeys() {
+			if err := resolveSecretFiles(v.MapIndex(key), fmt.Sprintf("%s.%v", name, key.Interface())); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveSecretFiles(v.Index(i), fmt.Sprintf("%s[%d]", name, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRedacted(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *Config
+	}{
+		{
+			name: "secrets",
+			cfg: &Config{
+				HTTPGateway: &HTTPGateway{Address: "127.0.0.1:9132", SigningKey: "key"},
+				Storage: &Storage{
+					Background: StorageBackgroundS3,
+					S3:         &fileio.S3Config{Bucket: "bucket", AccessKeyId: "id", SecretAccessKey: "secret"},
+				},
+			},
+			want: &Config{
+				HTTPGateway: &HTTPGateway{Address: "127.0.0.1:9132", SigningKey: redactedValue},
+				Storage: &Storage{
+					Background: StorageBackgroundS3,
+					S3:         &fileio.S3Config{Bucket: "bucket", AccessKeyId: redactedValue, SecretAccessKey: redactedValue},
+				},
+			},
+		},
+		{
+			name: "empty secrets",
+			cfg: &Config{
+				HTTPGateway: &HTTPGateway{Address: "127.0.0.1:9132"},
+				Storage:     &Storage{Background: StorageBackgroundS3, S3: &fileio.S3Config{Bucket: "bucket"}},
+			},
+			want: &Config{
+				HTTPGateway: &HTTPGateway{Address: "127.0.0.1:9132"},
+				Storage:     &Storage{Background: StorageBackgroundS3, S3: &fileio.S3Config{Bucket: "bucket"}},
+			},
+		},
+		{
+			name: "named storages",
+			cfg: &Config{
+				Storages: map[string]*Storage{
+					"archive": {Background: StorageBackgroundS3, S3: &fileio.S3Config{AccessKeyId: "id"}},
+					"hot":     {Background: StorageBackgroundHDFS},
+				},
+			},
+			want: &Config{
+				Storages: map[string]*Storage{
+					"archive": {Background: StorageBackgroundS3, S3: &fileio.S3Config{AccessKeyId: redactedValue}},
+					"hot":     {Background: StorageBackgroundHDFS},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := yaml.Marshal(tt.cfg)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			got, err := tt.cfg.Redacted()
+			if err != nil {
+				t.Fatalf("redacted: %v", err)
+			}
+			// The copy is compared by the dump, that is the way it is used.
+			gotYaml, _ := yaml.Marshal(got)
+			wantYaml, _ := yaml.Marshal(tt.want)
+			if !bytes.Equal(gotYaml, wantYaml) {
+				t.Fatalf("redacted:\n%s\nwant:\n%s", gotYaml, wantYaml)
+			}
+			// The original config is not modified.
+			if after, _ := yaml.Marshal(tt.cfg); !bytes.Equal(after, before) {
+				t.Fatalf("the original config is modified:\n%s", after)
+			}
+		})
+	}
+}
+
+func TestResolveSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	if err := os.WriteFile(keyFile, []byte("  rotated\n"), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	missingFile := filepath.Join(dir, "none")
+
+	tests := []struct {
+		name string
+		cfg  *Config
+		want *Config
+		err  string
+	}{
+		{
+			name: "plain values",
+			cfg: &Config{
+				HTTPGateway: &HTTPGateway{SigningKey: "key"},
+				Storage:     &Storage{S3: &fileio.S3Config{AccessKeyId: "id"}},
+			},
+			want: &Config{
+				HTTPGateway: &HTTPGateway{SigningKey: "key"},
+				Storage:     &Storage{S3: &fileio.S3Config{AccessKeyId: "id"}},
+			},
+		},
+		{
+			name: "file",
+			cfg: &Config{
+				HTTPGateway: &HTTPGateway{SigningKey: secretFilePrefix + keyFile},
+				Storage:     &Storage{S3: &fileio.S3Config{Bucket: secretFilePrefix + keyFile, SecretAccessKey: secretFilePrefix + keyFile}},
+			},
+			want: &Config{
+				HTTPGateway: &HTTPGateway{SigningKey: "rotated"},
+				// Only the secret fields are resolved.
+				Storage: &Storage{S3: &fileio.S3Config{Bucket: secretFilePrefix + keyFile, SecretAccessKey: "rotated"}},
+			},
+		},
+		{
+			name: "named storages",
+			cfg: &Config{
+				Storages: map[string]*Storage{"archive": {S3: &fileio.S3Config{AccessKeyId: secretFilePrefix + keyFile}}},
+			},
+			want: &Config{
+				Storages: map[string]*Storage{"archive": {S3: &fileio.S3Config{AccessKeyId: "rotated"}}},
+			},
+		},
+		{
+			name: "missing file",
+			cfg: &Config{
+				Storage: &Storage{S3: &fileio.S3Config{SecretAccessKey: secretFilePrefix + missingFile}},
+			},
+			err: "<storage.s3.secret_access_key>",
+		},
+		{
+			name: "missing file in named storages",
+			cfg: &Config{
+				Storages: map[string]*Storage{"archive": {S3: &fileio.S3Config{AccessKeyId: secretFilePrefix + missingFile}}},
+			},
+			err: "<storages.archive.s3.access_key_id>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := resolveSecretFiles(reflect.ValueOf(tt.cfg), "")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) || !errors.Is(err, os.ErrNotExist) {
+					t.Fatalf("error = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if !reflect.DeepEqual(tt.cfg, tt.want) {
+				t.Fatalf("resolved = %+v, want %+v", tt.cfg, tt.want)
+			}
+		})
+	}
+}
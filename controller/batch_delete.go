@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+)
+
+// The status of DeleteResult.
+const (
+	DeleteStatusDeleted  = "deleted"
+	DeleteStatusNotFound = "not_found"
+	DeleteStatusFailed   = "failed"
+	// DeleteStatusSkipped means not attempted because of the previous failure.
+	DeleteStatusSkipped = "skipped"
+)
+
+// deleteConcurrency returns the max number of resource files to delete concurrently.
+func deleteConcurrency() int {
+	if options.Config != nil && options.Config.Storage != nil && options.Config.Storage.DeleteConcurrency > 0 {
+		return options.Config.Storage.DeleteConcurrency
+	}
+	return 1
+}
+
+// batchDelete calls fn for each id with bounded concurrency, and returns the results in the same
+// order as ids and the first error. If continueOnError is false, the ids not started after a failure
+// are skipped. The not exists error is treated as success, so it is safe to retry.
+func batchDelete(ctx context.Context, ids []string, continueOnError bool,
+	fn func(ctx context.Context, id string) error) ([]*pbstoreio.DeleteResult, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		failed   int32
+	)
+
+	stopped := func() bool {
+		return (!continueOnError && atomic.LoadInt32(&failed) != 0) || ctx.Err() != nil
+	}
+
+	sem := make(chan struct{}, deleteConcurrency())
+	results := make([]*pbstoreio.DeleteResult, len(ids))
+	for i, id := range ids {
+		results[i] = &pbstoreio.DeleteResult{Id: id, Status: DeleteStatusSkipped}
+		if stopped() {
+			continue
+		}
+		sem <- struct{}{}
+		// Check again because the failure may happen during waiting.
+		if stopped() {
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *pbstoreio.DeleteResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := fn(ctx, result.Id)
+			switch {
+			case err == nil:
+				result.Status = DeleteStatusDeleted
+			case os.IsNotExist(err):
+				result.Status = DeleteStatusNotFound
+			default:
+				result.Status = DeleteStatusFailed
+				result.Reason = err.Error()
+				atomic.StoreInt32(&failed, 1)
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(results[i])
+	}
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		for _, result := range results {
+			if result.Status == DeleteStatusSkipped {
+				result.Reason = "not attempted because of the previous failure: " + firstErr.Error()
+			}
+		}
+	}
+	return results, firstErr
+}
+
+// deleteResourceFile removes all versions of the resource file or moves it to trash.
+// Returns an error that satisfies os.IsNotExist if the resource file not exists.
+func (x *StoreIo) deleteResourceFile(ctx context.Context, fs fileio.FileIO, spaceId, fileId string) error {
+	if trashEnabled() {
+		return x.moveToTrash(ctx, fs, spaceId, fileId, "")
+	}
+	fileDir := x.generateResourceFileDir(spaceId, fileId)
+	// The RemoveAll returns nil if not exists, so check it first to report not found.
+	if _, err := fs.Stat(ctx, fileDir); err != nil {
+		return err
+	}
+	return fs.RemoveAll(ctx, fileDir)
+}
+
+// deleteWorkspace deletes all resource files in workspace concurrently, and then removes the workspace
+// dir if trash disabled. Returns an error that satisfies os.IsNotExist if the workspace not exists.
+func (x *StoreIo) deleteWorkspace(ctx context.Context, fs fileio.FileIO, spaceId string, continueOnError bool) error {
+	lg := glog.FromContext(ctx)
+
+	workspaceDir := x.generateWorkspaceDir(spaceId)
+	infos, err := fs.List(ctx, workspaceDir)
+	if err != nil {
+		return err
+	}
+	fileIds := make([]string, 0, len(infos))
+	for _, info := range infos {
+		name := path.Base(info.Name)
+		if info.IsDir && !strings.HasPrefix(name, ".") {
+			fileIds = append(fileIds, name)
+		}
+	}
+
+	results, err := batchDelete(ctx, fileIds, continueOnError, func(ctx context.Context, fileId string) error {
+		return x.deleteResourceFile(ctx, fs, spaceId, fileId)
+	})
+	if err != nil {
+		var n int
+		for _, result := range results {
+			if result.Status == DeleteStatusFailed || result.Status == DeleteStatusSkipped {
+				n++
+			}
+		}
+		lg.Error().Msg("delete resource files in workspace failed").String("spaceId", spaceId).
+			Int("notDeleted", n).Int("total", len(results)).Error("error", err).Fire()
+		return fmt.Errorf("%d of %d resource files not deleted: %w", n, len(results), err)
+	}
+
+	// The trash is kept in workspace dir until purged.
+	if trashEnabled() {
+		return nil
+	}
+	return fs.RemoveAll(ctx, workspaceDir)
+}
+
+// batchDeleteFileData deletes the resource files in workspace.
+func (x *StoreIo) batchDeleteFileData(ctx context.Context, spaceId string, fileIds []string,
+	continueOnError bool) ([]*pbstoreio.DeleteResult, error) {
+	ctx, err := x.withProxyUser(ctx, spaceId)
+	if err != nil {
+		return nil, err
+	}
+	fs := x.storage(spaceId)
+	return batchDelete(ctx, fileIds, continueOnError, func(ctx context.Context, fileId string) error {
+		return x.deleteResourceFile(ctx, fs, spaceId, fileId)
+	})
+}
+
+// batchDeleteWorkspaces deletes the workspaces one by one, the resource files in each workspace
+// are deleted concurrently.
+func (x *StoreIo) batchDeleteWorkspaces(ctx context.Context, spaceIds []string,
+	continueOnError bool) ([]*pbstoreio.DeleteResult, error) {
+	var firstErr error
+	results := make([]*pbstoreio.DeleteResult, len(spaceIds))
+	for i, spaceId := range spaceIds {
+		results[i] = &pbstoreio.DeleteResult{Id: spaceId, Status: DeleteStatusSkipped}
+		if firstErr != nil && !continueOnError {
+			results[i].Reason = "not attempted because of the previous failure: " + firstErr.Error()
+			continue
+		}
+		spaceCtx, err := x.withProxyUser(ctx, spaceId)
+		if err == nil {
+			err = x.deleteWorkspace(spaceCtx, x.storage(spaceId), spaceId, continueOnError)
+		}
+		switch {
+		case err == nil:
+			results[i].Status = DeleteStatusDeleted
+		case os.IsNotExist(err):
+			results[i].Status = DeleteStatusNotFound
+		default:
+			results[i].Status = DeleteStatusFailed
+			results[i].Reason = err.Error()
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return results, firstErr
+}
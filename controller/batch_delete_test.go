@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+)
+
+func TestBatchDelete(t *testing.T) {
+	errFailed := errors.New("failed")
+	fn := func(ctx context.Context, id string) error {
+		switch id {
+		case "none":
+			return os.ErrNotExist
+		case "bad":
+			return errFailed
+		}
+		return nil
+	}
+	tests := []struct {
+		name            string
+		ids             []string
+		continueOnError bool
+		cancelled       bool
+		statuses        []string
+		err             error
+	}{
+		{
+			name:     "deleted",
+			ids:      []string{"a", "b"},
+			statuses: []string{DeleteStatusDeleted, DeleteStatusDeleted},
+		},
+		{
+			name:     "not found",
+			ids:      []string{"a", "none"},
+			statuses: []string{DeleteStatusDeleted, DeleteStatusNotFound},
+		},
+		{
+			name:     "stop on error",
+			ids:      []string{"a", "bad", "b", "none"},
+			statuses: []string{DeleteStatusDeleted, DeleteStatusFailed, DeleteStatusSkipped, DeleteStatusSkipped},
+			err:      errFailed,
+		},
+		{
+			name:            "continue on error",
+			ids:             []string{"a", "bad", "b", "none"},
+			continueOnError: true,
+			statuses:        []string{DeleteStatusDeleted, DeleteStatusFailed, DeleteStatusDeleted, DeleteStatusNotFound},
+			err:             errFailed,
+		},
+		{
+			name:            "cancelled",
+			ids:             []string{"a", "b"},
+			continueOnError: true,
+			cancelled:       true,
+			statuses:        []string{DeleteStatusSkipped, DeleteStatusSkipped},
+			err:             context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Delete one by one, so the ids after the failure are not started.
+			setupStorage(t, func(cfg *config.Config) {
+				cfg.Storage.DeleteConcurrency = 1
+			})
+			ctx, cancel := context.WithCancel(testContext())
+			defer cancel()
+			if tt.cancelled {
+				cancel()
+			}
+
+			results, err := batchDelete(ctx, tt.ids, tt.continueOnError, fn)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			if len(results) != len(tt.ids) {
+				t.Fatalf("results = %d, want %d", len(results), len(tt.ids))
+			}
+			for i, result := range results {
+				if result.Id != tt.ids[i] || result.Status != tt.statuses[i] {
+					t.Fatalf("result %d = %v, want %s %s", i, result, tt.ids[i], tt.statuses[i])
+				}
+				switch result.Status {
+				case DeleteStatusFailed:
+					if result.Reason != errFailed.Error() {
+						t.Fatalf("reason = %q", result.Reason)
+					}
+				case DeleteStatusSkipped:
+					if !strings.Contains(result.Reason, "previous failure") {
+						t.Fatalf("reason = %q", result.Reason)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteFileDataByIds(t *testing.T) {
+	x := new(StoreIo)
+	fs := setupStorage(t, nil)
+	ctx := testContext()
+	if _, err := writeFileData(x, testVersion, "hello", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	const otherFileId = "res-0000000000000002"
+	reply, err := x.DeleteFileDataByFileIds(ctx, &pbstoreio.DeleteFileDataByFileIdsRequest{
+		SpaceId: testSpaceId, FileIds: []string{testFileId, otherFileId},
+	})
+	if err != nil {
+		t.Fatalf("delete by file ids: %v", err)
+	}
+	if len(reply.Results) != 2 || reply.Results[0].Status != DeleteStatusDeleted || reply.Results[1].Status != DeleteStatusNotFound {
+		t.Fatalf("results = %v", reply.Results)
+	}
+	if exists, err := fs.IsExists(ctx, x.generateResourceFileDir(testSpaceId, testFileId)); err != nil || exists {
+		t.Fatalf("resource file exists = %v, %v", exists, err)
+	}
+
+	const otherSpaceId = "wks-0000000000000002"
+	reply, err = x.DeleteFileDataBySpaceIds(ctx, &pbstoreio.DeleteFileDataBySpaceIdsRequest{
+		SpaceIds: []string{testSpaceId, otherSpaceId},
+	})
+	if err != nil {
+		t.Fatalf("delete by space ids: %v", err)
+	}
+	if len(reply.Results) != 2 || reply.Results[0].Status != DeleteStatusDeleted || reply.Results[1].Status != DeleteStatusNotFound {
+		t.Fatalf("results = %v", reply.Results)
+	}
+	if exists, err := fs.IsExists(ctx, x.generateWorkspaceDir(testSpaceId)); err != nil || exists {
+		t.Fatalf("workspace exists = %v, %v", exists, err)
+	}
+}
+
+// failingRemoveFS fails to remove the names that contain failed.
+type failingRemoveFS struct {
+	fileio.FileIO
+	failed string
+}
+
+func (fs *failingRemoveFS) RemoveAll(ctx context.Context, name string) error {
+	if strings.Contains(name, fs.failed) {
+		return errors.New("remove failed")
+	}
+	return fs.FileIO.RemoveAll(ctx, name)
+}
+
+func TestDeleteFileDataByIdsStopOnError(t *testing.T) {
+	x := new(StoreIo)
+	mem := setupStorage(t, func(cfg *config.Config) {
+		cfg.Storage.DeleteConcurrency = 1
+	})
+	ctx := testContext()
+	fileIds := []string{"res-0000000000000001", "res-0000000000000002", "res-0000000000000003"}
+	for _, fileId := range fileIds {
+		if err := mem.MkdirAll(ctx, x.generateResourceFileDir(testSpaceId, fileId), 0777); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	options.Storages = fileio.NewRegistry()
+	if err := options.Storages.Register(config.DefaultStorageName, &failingRemoveFS{FileIO: mem, failed: fileIds[1]}); err != nil {
+		t.Fatalf("register storage: %v", err)
+	}
+
+	reply, err := x.DeleteFileDataByFileIds(ctx, &pbstoreio.DeleteFileDataByFileIdsRequest{
+		SpaceId: testSpaceId, FileIds: fileIds,
+	})
+	if err != nil {
+		t.Fatalf("delete by file ids: %v", err)
+	}
+	want := []string{DeleteStatusDeleted, DeleteStatusFailed, DeleteStatusSkipped}
+	if len(reply.Results) != len(want) {
+		t.Fatalf("results = %v", reply.Results)
+	}
+	for i, result := range reply.Results {
+		if result.Id != fileIds[i] || result.Status != want[i] {
+			t.Fatalf("result %d = %v, want %s %s", i, result, fileIds[i], want[i])
+		}
+	}
+	if exists, err := mem.IsExists(ctx, x.generateResourceFileDir(testSpaceId, fileIds[2])); err != nil || !exists {
+		t.Fatalf("skipped resource file exists = %v, %v", exists, err)
+	}
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+	"google.golang.org/grpc/metadata"
+)
+
+// The gRPC metadata keys to transfer the digest of file data. The client specifies them
+// in request to verify the uploaded data, and the server returns them in response header.
+const (
+	checksumMD5MetadataKey    = "x-checksum-md5"
+	checksumSHA256MetadataKey = "x-checksum-sha256"
+)
+
+// The digest of resource file is stored in a sidecar file `.digest/<version>` under resource file dir.
+const digestDirName = ".digest"
+
+func (x *StoreIo) generateDigestPath(spaceId, fileId, version string) string {
+	return x.generateResourceFileDir(spaceId, fileId) + "/" + digestDirName + "/" + version
+}
+
+// expectedDigestFromMetadata returns the digest specified by client in gRPC metadata.
+// Returns nil if not specified.
+func expectedDigestFromMetadata(ctx context.Context) (*fileio.Digest, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	expected := new(fileio.Digest)
+	if values := md.Get(checksumMD5MetadataKey); len(values) > 0 {
+		if len(values[0]) != 32 {
+			return nil, qerror.InvalidParams.Format(checksumMD5MetadataKey)
+		}
+		expected.MD5 = values[0]
+	}
+	if values := md.Get(checksumSHA256MetadataKey); len(values) > 0 {
+		if len(values[0]) != 64 {
+			return nil, qerror.InvalidParams.Format(checksumSHA256MetadataKey)
+		}
+		expected.SHA256 = values[0]
+	}
+	if expected.MD5 == "" && expected.SHA256 == "" {
+		return nil, nil
+	}
+	return expected, nil
+}
+
+// digestMetadata converts the digest to gRPC metadata that returns to client.
+func digestMetadata(digest *fileio.Digest) metadata.MD {
+	return metadata.Pairs(checksumMD5MetadataKey, digest.MD5, checksumSHA256MetadataKey, digest.SHA256)
+}
+
+// createFileData writes the data to resource file and stores its digest in sidecar.
+// Nothing is published if the data mismatches the expected digest.
+func (x *StoreIo) createFileData(ctx context.Context, fs fileio.FileIO, spaceId, fileId, version string,
+	reader io.ReadCloser, expected *fileio.Digest) (*fileio.Digest, error) {
+	lg := glog.FromContext(ctx)
+
+	filePath := x.generateResourceFilePath(spaceId, fileId, version)
+	digestReader := fileio.NewDigestReader(reader, expected)
+	if _, err := fs.CreateAndWrite(ctx, filePath, digestReader); err != nil {
+		if digestReader.Mismatched() {
+			checksumMismatches.WithLabelValues(mismatchSourceUpload).Inc()
+			lg.Warn().Msg("the digest of file data mismatch").String("md5", digestReader.Digest().MD5).
+				String("sha256", digestReader.Digest().SHA256).Fire()
+			return nil, qerror.InvalidRequest.Format("the checksum of file data mismatch")
+		}
+		return nil, err
+	}
+
+	digest := digestReader.Digest()
+	// The file has been published, so only log the error. The verification reports no digest stored for it.
+	if err := x.writeDigest(ctx, fs, spaceId, fileId, version, digest); err != nil {
+		lg.Error().Msg("write digest of file data failed").String("version", version).Error("error", err).Fire()
+	}
+	return digest, nil
+}
+
+func (x *StoreIo) writeDigest(ctx context.Context, fs fileio.FileIO, spaceId, fileId, version string, digest *fileio.Digest) error {
+	digestPath := x.generateDigestPath(spaceId, fileId, version)
+	if err := fs.MkdirAll(ctx, x.generateResourceFileDir(spaceId, fileId)+"/"+digestDirName, 0777); err != nil {
+		return err
+	}
+	// Remove the stale digest left by the deleted file of same version.
+	if err := fs.Remove(ctx, digestPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return writeJSONFile(ctx, fs, digestPath, digest)
+}
+
+// readDigest reads the digest stored in sidecar. Returns nil if not exists.
+func (x *StoreIo) readDigest(ctx context.Context, fs fileio.FileIO, spaceId, fileId, version string) (*fileio.Digest, error) {
+	digest := new(fileio.Digest)
+	if err := readJSONFile(ctx, fs, x.generateDigestPath(spaceId, fileId, version), digest); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return digest, nil
+}
+
+// verifyFileData reads the whole resource file and compares its digest with the stored.
+// The read speed is limited by limiter if it is not nil.
+func (x *StoreIo) verifyFileData(ctx context.Context, fs fileio.FileIO, spaceId, fileId, version string,
+	limiter *rateLimiter) (*pbstoreio.VerifyFileDataReply, error) {
+	stored, err := x.readDigest(ctx, fs, spaceId, fileId, version)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := fs.OpenForRead(ctx, x.generateResourceFilePath(spaceId, fileId, version))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	digest, size, err := fileio.ComputeDigest(limiter.reader(ctx, reader))
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &pbstoreio.VerifyFileDataReply{
+		Size:   size,
+		Md5:    digest.MD5,
+		Sha256: digest.SHA256,
+	}
+	if stored != nil {
+		reply.StoredMd5 = stored.MD5
+		reply.StoredSha256 = stored.SHA256
+		reply.Matched = digest.Match(stored)
+	}
+	return reply, nil
+}
+
+// VerifyFileData recomputes the digest of resource file and compares it with the digest stored at upload.
+func (x *StoreIo) VerifyFileData(ctx context.Context, req *pbstoreio.VerifyFileDataRequest) (*pbstoreio.VerifyFileDataReply, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, err := x.withProxyUser(ctx, req.SpaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := x.verifyFileData(ctx, x.storage(req.SpaceId), req.SpaceId, req.FileId, req.Version, nil)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, qerror.ResourceNotExists.Format(req.FileId)
+		}
+		return nil, err
+	}
+	return reply, nil
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestExpectedDigestFromMetadata(t *testing.T) {
+	md5 := md5Hex("hello")
+	sha256 := strings.Repeat("a", 64)
+	tests := []struct {
+		name   string
+		md     metadata.MD
+		md5    string
+		sha256 string
+		err    *qerror.Error
+	}{
+		{name: "no metadata"},
+		{name: "not specified", md: metadata.Pairs("x-request-id", "1")},
+		{name: "md5", md: metadata.Pairs(checksumMD5MetadataKey, md5), md5: md5},
+		{name: "both", md: metadata.Pairs(checksumMD5MetadataKey, md5, checksumSHA256MetadataKey, sha256), md5: md5, sha256: sha256},
+		{name: "invalid md5", md: metadata.Pairs(checksumMD5MetadataKey, "abc"), err: qerror.InvalidParams},
+		{name: "invalid sha256", md: metadata.Pairs(checksumSHA256MetadataKey, md5), err: qerror.InvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			expected, err := expectedDigestFromMetadata(ctx)
+			if tt.err != nil {
+				if !isError(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected digest: %v", err)
+			}
+			if tt.md5 == "" && tt.sha256 == "" {
+				if expected != nil {
+					t.Fatalf("expected = %+v, want nil", expected)
+				}
+				return
+			}
+			if expected == nil || expected.MD5 != tt.md5 || expected.SHA256 != tt.sha256 {
+				t.Fatalf("expected = %+v", expected)
+			}
+		})
+	}
+}
+
+func TestVerifyFileData(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt bool
+		noStore bool
+		matched bool
+	}{
+		{name: "matched", matched: true},
+		{name: "corrupted", corrupt: true},
+		{name: "no digest stored", noStore: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := new(StoreIo)
+			fs := setupStorage(t, nil)
+			ctx := testContext()
+			if _, err := writeFileData(x, testVersion, "hello", nil); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			filePath := x.generateResourceFilePath(testSpaceId, testFileId, testVersion)
+			if tt.corrupt {
+				if err := fs.Remove(ctx, filePath); err != nil {
+					t.Fatalf("remove: %v", err)
+				}
+				if _, err := fs.CreateAndWrite(ctx, filePath, io.NopCloser(strings.NewReader("hellO"))); err != nil {
+					t.Fatalf("create: %v", err)
+				}
+			}
+			if tt.noStore {
+				if err := fs.Remove(ctx, x.generateDigestPath(testSpaceId, testFileId, testVersion)); err != nil {
+					t.Fatalf("remove digest: %v", err)
+				}
+			}
+
+			reply, err := x.VerifyFileData(ctx, &pbstoreio.VerifyFileDataRequest{SpaceId: testSpaceId, FileId: testFileId, Version: testVersion})
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			if reply.Matched != tt.matched || reply.Size != 5 {
+				t.Fatalf("reply = %v", reply)
+			}
+			if tt.noStore != (reply.StoredMd5 == "") {
+				t.Fatalf("stored md5 = %q", reply.StoredMd5)
+			}
+			if !tt.noStore && reply.StoredMd5 != md5Hex("hello") {
+				t.Fatalf("stored md5 = %q", reply.StoredMd5)
+			}
+		})
+	}
+
+	setupStorage(t, nil)
+	_, err := new(StoreIo).VerifyFileData(testContext(), &pbstoreio.VerifyFileDataRequest{SpaceId: testSpaceId, FileId: testFileId, Version: testVersion})
+	if !isError(err, qerror.ResourceNotExists) {
+		t.Fatalf("verify not exists: %v", err)
+	}
+}
@@ -0,0 +1,467 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DataWorkbench/common/gtrace"
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIdHeader is the HTTP header that carries the request id, it is logged as trace id.
+const requestIdHeader = "X-Request-Id"
+
+// multipartFileField is the form field of the file data in multipart upload.
+const multipartFileField = "file"
+
+// gatewayMetadataKeys are the gRPC metadata keys that read from the HTTP headers with same
+// names, so the gateway shares the checksum handling with StoreIo. The hadoop user is not
+// read, because the web pages must not act as the users they choose.
+var gatewayMetadataKeys = []string{checksumMD5MetadataKey, checksumSHA256MetadataKey}
+
+// httpError is the error with the HTTP status that qerror not defined.
+type httpError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.code
+}
+
+var (
+	errRangeNotSatisfiable = &httpError{status: http.StatusRequestedRangeNotSatisfiable, code: "RangeNotSatisfiable",
+		message: "the range is not satisfiable"}
+	errUploadTooLarge = &httpError{status: http.StatusRequestEntityTooLarge, code: "RequestEntityTooLarge",
+		message: "the file size exceeds the limit"}
+	errGatewayShuttingDown = &httpError{status: http.StatusServiceUnavailable, code: "ServiceUnavailable",
+		message: "the server is shutting down"}
+)
+
+// HTTPGateway serves the upload and download of resource files over HTTP at
+// /{spaceId}/{fileId}/{version}, with the same storages and paths as StoreIo.
+//   - PUT uploads the request body, POST uploads the "file" field of multipart form.
+//     The checksum can be specified in header X-Checksum-Md5 or X-Checksum-Sha256.
+//   - GET and HEAD download the file, with Range, ETag and If-None-Match supported.
+//
+// The requests must be signed by SignGatewayPath, and the web pages of allowed origins can
+// access by CORS.
+type HTTPGateway struct {
+	x              *StoreIo
+	lp             *glog.Logger
+	maxUploadSize  int64 // In bytes, 0 means unlimited.
+	signingKey     string
+	allowedOrigins []string
+}
+
+// NewHTTPGateway creates a HTTPGateway that logs to lp.
+func NewHTTPGateway(lp *glog.Logger, cfg *config.HTTPGateway) *HTTPGateway {
+	return &HTTPGateway{
+		x:              &StoreIo{},
+		lp:             lp,
+		maxUploadSize:  cfg.MaxUploadSize * 1024 * 1024,
+		signingKey:     cfg.SigningKey,
+		allowedOrigins: cfg.CORSAllowedOrigins,
+	}
+}
+
+func (g *HTTPGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reqId := r.Header.Get(requestIdHeader)
+	nl := g.lp.Clone()
+	nl.WithFields().AddString(gtrace.IdKey, reqId)
+	defer func() {
+		_ = nl.Close()
+	}()
+	nl.Debug().String("http gateway receive", r.Method+" "+r.URL.Path).Fire()
+
+	if g.setCORSHeaders(w, r) {
+		return
+	}
+
+	ctx := gtrace.ContextWithId(glog.WithContext(r.Context(), nl), reqId)
+	md := metadata.MD{}
+	for _, key := range gatewayMetadataKeys {
+		if value := r.Header.Get(key); value != "" {
+			md.Set(key, value)
+		}
+	}
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	err := g.authorize(r)
+	var spaceId, fileId, version string
+	if err == nil {
+		spaceId, fileId, version, err = parseGatewayPath(r.URL.Path)
+	}
+	if err == nil {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			err = g.download(ctx, w, r, spaceId, fileId, version)
+		case http.MethodPut, http.MethodPost:
+			err = g.upload(ctx, w, r, spaceId, fileId, version)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+			err = qerror.MethodNotAllowed
+		}
+	}
+	if err != nil {
+		g.writeError(ctx, w, reqId, err)
+	}
+}
+
+func validateSpaceId(spaceId string) error {
+	if len(spaceId) != 20 || !strings.HasPrefix(spaceId, "wks-") {
+		return qerror.InvalidParams.Format("space_id")
+	}
+	return nil
+}
+
+func validateFileId(fileId string) error {
+	if len(fileId) != 20 || !strings.HasPrefix(fileId, "res-") {
+		return qerror.InvalidParams.Format("file_id")
+	}
+	return nil
+}
+
+func validateVersion(version string) error {
+	if len(version) != 16 {
+		return qerror.InvalidParams.Format("version")
+	}
+	return nil
+}
+
+// parseGatewayPath parses the ids from path /{spaceId}/{fileId}/{version}.
+func parseGatewayPath(path string) (spaceId, fileId, version string, err error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 3 {
+		return "", "", "", qerror.ResourceNotExists.Format(path)
+	}
+	spaceId, fileId, version = parts[0], parts[1], parts[2]
+	if err = validateSpaceId(spaceId); err != nil {
+		return
+	}
+	if err = validateFileId(fileId); err != nil {
+		return
+	}
+	err = validateVersion(version)
+	return
+}
+
+func (g *HTTPGateway) upload(ctx context.Context, w http.ResponseWriter, r *http.Request, spaceId, fileId, version string) (err error) {
+	defer trackStream("HTTPUpload")()
+	ctx, end, err := beginTransfer(ctx, Transfer{Method: "HTTPUpload", SpaceId: spaceId, FileId: fileId, Version: version})
+	if err != nil {
+		return
+	}
+	defer end()
+	defer func() {
+		err = drainError(ctx, err)
+	}()
+
+	var body io.Reader = r.Body
+	if r.Method == http.MethodPost {
+		if body, err = multipartFile(r); err != nil {
+			return
+		}
+	}
+
+	// The client can specify the expected checksum in headers to verify the data.
+	expected, err := expectedDigestFromMetadata(ctx)
+	if err != nil {
+		return
+	}
+
+	if ctx, err = g.x.withProxyUser(ctx, spaceId); err != nil {
+		return
+	}
+	fs := g.x.storage(spaceId)
+	if err = g.x.ensureRootDirExists(ctx, fs, spaceId, fileId); err != nil {
+		return
+	}
+
+	// The storage aborts the write if the reader failed, so nothing is published if the
+	// body is incomplete, too large or the transfer cancelled.
+	reader := &transferReader{ctx: ctx, r: body, limit: g.maxUploadSize}
+	digest, err := g.x.createFileData(ctx, fs, spaceId, fileId, version, io.NopCloser(reader), expected)
+	if err != nil {
+		switch {
+		case reader.exceeded:
+			return errUploadTooLarge
+		case os.IsExist(err):
+			return qerror.ResourceAlreadyExists.Format(version)
+		}
+		return
+	}
+
+	glog.FromContext(ctx).Debug().Msg("http gateway: file data uploaded").Int64("size", reader.n).
+		String("eTag", digest.MD5).Fire()
+	transferBytes.WithLabelValues("upload").Add(float64(reader.n))
+	observeUpload(reader.n)
+
+	setDigestHeaders(w, digest)
+	w.Header().Set("ETag", quoteETag(digest.MD5))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(&pbstoreio.CommitUploadSessionReply{Etag: digest.MD5, Sha256: digest.SHA256})
+	return
+}
+
+// multipartFile returns the reader of the file field in multipart form, the parts before
+// it are skipped.
+func multipartFile(r *http.Request) (io.Reader, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, qerror.InvalidRequest.Format(err.Error())
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, qerror.InvalidParams.Format(multipartFileField)
+		}
+		if err != nil {
+			return nil, qerror.InvalidRequest.Format(err.Error())
+		}
+		if part.FormName() == multipartFileField {
+			return part, nil
+		}
+	}
+}
+
+func (g *HTTPGateway) download(ctx context.Context, w http.ResponseWriter, r *http.Request, spaceId, fileId, version string) (err error) {
+	defer trackStream("HTTPDownload")()
+	ctx, end, err := beginTransfer(ctx, Transfer{Method: "HTTPDownload", SpaceId: spaceId, FileId: fileId, Version: version})
+	if err != nil {
+		return
+	}
+	defer end()
+	defer func() {
+		err = drainError(ctx, err)
+	}()
+
+	if ctx, err = g.x.withProxyUser(ctx, spaceId); err != nil {
+		return
+	}
+	lg := glog.FromContext(ctx)
+	fs := g.x.storage(spaceId)
+	filePath := g.x.generateResourceFilePath(spaceId, fileId, version)
+	info, err := fs.Stat(ctx, filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return qerror.ResourceNotExists.Format(fileId)
+		}
+		return
+	}
+
+	// The ETag is the MD5 stored at upload, or the one of storage if no digest stored.
+	eTag := info.ETag
+	digest, derr := g.x.readDigest(ctx, fs, spaceId, fileId, version)
+	if derr != nil {
+		lg.Warn().Msg("read digest of file data failed").Error("error", derr).Fire()
+	}
+	if digest != nil {
+		eTag = digest.MD5
+		setDigestHeaders(w, digest)
+	}
+	header := w.Header()
+	if eTag != "" {
+		header.Set("ETag", quoteETag(eTag))
+	}
+	header.Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+	header.Set("Accept-Ranges", "bytes")
+	if eTag != "" && matchETag(r.Header.Get("If-None-Match"), eTag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	offset, length, partial := int64(0), info.Size, false
+	// Serve the whole file if If-Range mismatches, the file has been changed.
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" && matchIfRange(r.Header.Get("If-Range"), eTag) {
+		if offset, length, partial, err = parseRange(rangeHeader, info.Size); err != nil {
+			header.Set("Content-Range", fmt.Sprintf("bytes */%d", info.Size))
+			return
+		}
+	}
+
+	var reader io.ReadCloser
+	if r.Method == http.MethodGet {
+		if partial {
+			reader, err = g.x.storageForRead(spaceId).OpenRange(ctx, filePath, offset, length)
+		} else {
+			reader, err = g.x.storageForRead(spaceId).OpenForRead(ctx, filePath)
+		}
+		if err != nil {
+			if os.IsNotExist(err) {
+				return qerror.ResourceNotExists.Format(fileId)
+			}
+			return
+		}
+		defer func() {
+			_ = reader.Close()
+		}()
+	}
+
+	header.Set("Content-Type", "application/octet-stream")
+	header.Set("Content-Length", strconv.FormatInt(length, 10))
+	status := http.StatusOK
+	if partial {
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, info.Size))
+		status = http.StatusPartialContent
+	}
+	w.WriteHeader(status)
+	if reader == nil {
+		return nil
+	}
+
+	n, cerr := io.Copy(w, &transferReader{ctx: ctx, r: reader})
+	transferBytes.WithLabelValues("download").Add(float64(n))
+	if cerr != nil {
+		// The response has been started, so only log the error.
+		lg.Warn().Msg("http gateway: send file data failed").Int64("sent", n).Error("error", cerr).Fire()
+	}
+	return nil
+}
+
+// parseRange parses the Range header of single range. The partial is false if the header
+// should be ignored, such as multiple ranges or the invalid syntax.
+func parseRange(header string, size int64) (offset, length int64, partial bool, err error) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) || strings.Contains(header, ",") {
+		return 0, size, false, nil
+	}
+	spec := strings.TrimSpace(strings.TrimPrefix(header, prefix))
+	i := strings.Index(spec, "-")
+	if i < 0 {
+		return 0, size, false, nil
+	}
+	start, end := strings.TrimSpace(spec[:i]), strings.TrimSpace(spec[i+1:])
+	if start == "" {
+		// The suffix range, the last n bytes.
+		n, perr := strconv.ParseInt(end, 10, 64)
+		if perr != nil || n < 0 {
+			return 0, size, false, nil
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, false, errRangeNotSatisfiable
+		}
+		if n > size {
+			n = size
+		}
+		return size - n, n, true, nil
+	}
+
+	first, perr := strconv.ParseInt(start, 10, 64)
+	if perr != nil || first < 0 {
+		return 0, size, false, nil
+	}
+	last := size - 1
+	if end != "" {
+		if last, perr = strconv.ParseInt(end, 10, 64); perr != nil || last < first {
+			return 0, size, false, nil
+		}
+		if last > size-1 {
+			last = size - 1
+		}
+	}
+	if first >= size {
+		return 0, 0, false, errRangeNotSatisfiable
+	}
+	return first, last - first + 1, true, nil
+}
+
+// matchETag reports whether the If-None-Match header matches the eTag, the weak comparison is used.
+func matchETag(header string, eTag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == quoteETag(eTag) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIfRange reports whether the range should be served for the If-Range header.
+// Only the strong ETag is supported, the date is regarded as mismatched.
+func matchIfRange(header string, eTag string) bool {
+	return header == "" || (eTag != "" && header == quoteETag(eTag))
+}
+
+func quoteETag(eTag string) string {
+	return `"` + eTag + `"`
+}
+
+// setDigestHeaders sets the digest to the headers with the same names as gRPC metadata.
+func setDigestHeaders(w http.ResponseWriter, digest *fileio.Digest) {
+	w.Header().Set(checksumMD5MetadataKey, digest.MD5)
+	if digest.SHA256 != "" {
+		w.Header().Set(checksumSHA256MetadataKey, digest.SHA256)
+	}
+}
+
+// writeError writes the error as qerror.Response in json.
+func (g *HTTPGateway) writeError(ctx context.Context, w http.ResponseWriter, reqId string, err error) {
+	lg := glog.FromContext(ctx)
+	if err == ErrShuttingDown {
+		err = errGatewayShuttingDown
+	}
+
+	var (
+		resp *qerror.Response
+		he   *httpError
+		qe   *qerror.Error
+	)
+	switch {
+	case errors.As(err, &he):
+		resp = &qerror.Response{Code: he.code, Status: he.status, RequestID: reqId, Detail: qerror.Detail{EnUs: he.message, ZhCn: he.message}}
+	case errors.As(err, &qe):
+		resp = qerror.NewResponse(qe, reqId)
+	default:
+		resp = qerror.NewResponse(qerror.Internal, reqId)
+	}
+	if resp.Status >= http.StatusInternalServerError {
+		lg.Error().Msg("http gateway: handle request failed").Int("status", resp.Status).Error("error", err).Fire()
+	} else {
+		lg.Info().Msg("http gateway: request failed").Int("status", resp.Status).String("code", resp.Code).Fire()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.Status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// transferReader counts the bytes read and fails once ctx is done or the limit exceeded,
+// so the storage aborts the write and the download stops when the transfer cancelled.
+type transferReader struct {
+	ctx      context.Context
+	r        io.Reader
+	limit    int64 // 0 means unlimited.
+	n        int64
+	exceeded bool
+}
+
+func (r *transferReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if r.limit > 0 && r.n > r.limit {
+		r.exceeded = true
+		return n, errUploadTooLarge
+	}
+	return n, err
+}
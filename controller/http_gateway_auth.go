@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataWorkbench/common/qerror"
+)
+
+// The query parameters of signed gateway URL.
+const (
+	gatewayExpiresParam   = "expires"
+	gatewaySignatureParam = "signature"
+)
+
+// The headers that the web pages can send and read by CORS.
+const (
+	corsAllowMethods  = "GET, HEAD, PUT, POST"
+	corsAllowHeaders  = "Content-Type, Range, If-None-Match, If-Range, X-Checksum-Md5, X-Checksum-Sha256, X-Request-Id"
+	corsExposeHeaders = "Accept-Ranges, Content-Length, Content-Range, ETag, X-Checksum-Md5, X-Checksum-Sha256"
+	corsMaxAge        = "600"
+)
+
+// signMethod returns the method in signature, the download and upload are signed as GET and PUT.
+func signMethod(method string) string {
+	switch method {
+	case http.MethodHead:
+		return http.MethodGet
+	case http.MethodPost:
+		return http.MethodPut
+	}
+	return method
+}
+
+// gatewaySignature returns the hex HMAC-SHA256 of the method, path and expires with key.
+func gatewaySignature(key string, method string, path string, expires string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	_, _ = mac.Write([]byte(signMethod(method) + "\n" + path + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignGatewayPath returns the query that authorizes the method on the path of HTTPGateway
+// until expires, key is the `http_gateway.signing_key` in config. The services that issue
+// the upload or download URL to web pages append it to the path, such as
+// `/{spaceId}/{fileId}/{version}?expires=1646200000&signature=<hex>`.
+func SignGatewayPath(key string, method string, path string, expires time.Time) url.Values {
+	value := strconv.FormatInt(expires.Unix(), 10)
+	return url.Values{
+		gatewayExpiresParam:   []string{value},
+		gatewaySignatureParam: []string{gatewaySignature(key, method, path, value)},
+	}
+}
+
+// authorize verifies the signature in query of the request.
+func (g *HTTPGateway) authorize(r *http.Request) error {
+	query := r.URL.Query()
+	expires := query.Get(gatewayExpiresParam)
+	signature := query.Get(gatewaySignatureParam)
+	if expires == "" || signature == "" {
+		return qerror.ParamsIsEmpty.Format(gatewaySignatureParam)
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return qerror.InvalidParams.Format(gatewayExpiresParam)
+	}
+	expected := gatewaySignature(g.signingKey, r.Method, r.URL.Path, expires)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return qerror.SignatureNotMatch
+	}
+	if time.Now().Unix() > unix {
+		return qerror.ExpiredSignature
+	}
+	return nil
+}
+
+// allowOrigin reports whether the web pages of origin are allowed to access by CORS.
+func (g *HTTPGateway) allowOrigin(origin string) bool {
+	for _, allowed := range g.allowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// setCORSHeaders sets the CORS headers if the origin of request is allowed, and reports
+// whether the request is a preflight that handled.
+func (g *HTTPGateway) setCORSHeaders(w http.ResponseWriter, r *http.Request) (preflight bool) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	w.Header().Add("Vary", "Origin")
+	if !g.allowOrigin(origin) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+		w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+	w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+	w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
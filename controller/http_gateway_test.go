@@ -0,0 +1,300 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header  string
+		size    int64
+		offset  int64
+		length  int64
+		partial bool
+		err     error
+	}{
+		{header: "bytes=0-4", size: 10, offset: 0, length: 5, partial: true},
+		{header: "bytes=5-", size: 10, offset: 5, length: 5, partial: true},
+		{header: "bytes=5-100", size: 10, offset: 5, length: 5, partial: true},
+		{header: "bytes=9-9", size: 10, offset: 9, length: 1, partial: true},
+		{header: "bytes=-3", size: 10, offset: 7, length: 3, partial: true},
+		{header: "bytes=-100", size: 10, offset: 0, length: 10, partial: true},
+		{header: "bytes= 2 - 3", size: 10, offset: 2, length: 2, partial: true},
+		// The invalid or unsupported ranges are ignored.
+		{header: "bytes=0-1,3-4", size: 10, offset: 0, length: 10},
+		{header: "items=0-1", size: 10, offset: 0, length: 10},
+		{header: "bytes=1", size: 10, offset: 0, length: 10},
+		{header: "bytes=a-1", size: 10, offset: 0, length: 10},
+		{header: "bytes=4-2", size: 10, offset: 0, length: 10},
+		{header: "bytes=--1", size: 10, offset: 0, length: 10},
+		// Not satisfiable.
+		{header: "bytes=10-", size: 10, err: errRangeNotSatisfiable},
+		{header: "bytes=10-20", size: 10, err: errRangeNotSatisfiable},
+		{header: "bytes=-0", size: 10, err: errRangeNotSatisfiable},
+		{header: "bytes=-1", size: 0, err: errRangeNotSatisfiable},
+		{header: "bytes=0-", size: 0, err: errRangeNotSatisfiable},
+	}
+	for _, tt := range tests {
+		offset, length, partial, err := parseRange(tt.header, tt.size)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("parseRange(%q, %d) error = %v, want %v", tt.header, tt.size, err, tt.err)
+			continue
+		}
+		if tt.err == nil && (offset != tt.offset || length != tt.length || partial != tt.partial) {
+			t.Errorf("parseRange(%q, %d) = %d, %d, %v, want %d, %d, %v", tt.header, tt.size,
+				offset, length, partial, tt.offset, tt.length, tt.partial)
+		}
+	}
+}
+
+func TestMatchETag(t *testing.T) {
+	tests := []struct {
+		header string
+		eTag   string
+		match  bool
+	}{
+		{header: "", eTag: "abc", match: false},
+		{header: `"abc"`, eTag: "abc", match: true},
+		{header: `W/"abc"`, eTag: "abc", match: true},
+		{header: `"xyz", "abc"`, eTag: "abc", match: true},
+		{header: "*", eTag: "abc", match: true},
+		{header: `"xyz"`, eTag: "abc", match: false},
+		{header: "abc", eTag: "abc", match: false},
+	}
+	for _, tt := range tests {
+		if got := matchETag(tt.header, tt.eTag); got != tt.match {
+			t.Errorf("matchETag(%q, %q) = %v, want %v", tt.header, tt.eTag, got, tt.match)
+		}
+	}
+}
+
+func TestMatchIfRange(t *testing.T) {
+	tests := []struct {
+		header string
+		eTag   string
+		match  bool
+	}{
+		{header: "", eTag: "abc", match: true},
+		{header: `"abc"`, eTag: "abc", match: true},
+		{header: `W/"abc"`, eTag: "abc", match: false},
+		{header: `"abc"`, eTag: "", match: false},
+		{header: "Wed, 21 Oct 2015 07:28:00 GMT", eTag: "abc", match: false},
+	}
+	for _, tt := range tests {
+		if got := matchIfRange(tt.header, tt.eTag); got != tt.match {
+			t.Errorf("matchIfRange(%q, %q) = %v, want %v", tt.header, tt.eTag, got, tt.match)
+		}
+	}
+}
+
+func TestParseGatewayPath(t *testing.T) {
+	tests := []struct {
+		path string
+		err  *qerror.Error
+	}{
+		{path: "/" + testSpaceId + "/" + testFileId + "/" + testVersion},
+		{path: "/" + testSpaceId + "/" + testFileId + "/" + testVersion + "/"},
+		{path: "/" + testSpaceId + "/" + testFileId, err: qerror.ResourceNotExists},
+		{path: "/" + testSpaceId + "/" + testFileId + "/" + testVersion + "/x", err: qerror.ResourceNotExists},
+		{path: "/wks-1/" + testFileId + "/" + testVersion, err: qerror.InvalidParams},
+		{path: "/" + testSpaceId + "/" + testSpaceId + "/" + testVersion, err: qerror.InvalidParams},
+		{path: "/" + testSpaceId + "/" + testFileId + "/1", err: qerror.InvalidParams},
+	}
+	for _, tt := range tests {
+		spaceId, fileId, version, err := parseGatewayPath(tt.path)
+		if tt.err != nil {
+			if !isError(err, tt.err) {
+				t.Errorf("parseGatewayPath(%q) error = %v, want %v", tt.path, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || spaceId != testSpaceId || fileId != testFileId || version != testVersion {
+			t.Errorf("parseGatewayPath(%q) = %s, %s, %s, %v", tt.path, spaceId, fileId, version, err)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	const key = "key"
+	path := "/" + testSpaceId + "/" + testFileId + "/" + testVersion
+	g := &HTTPGateway{signingKey: key}
+	expires := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		err    *qerror.Error
+	}{
+		{name: "get", method: http.MethodGet, query: SignGatewayPath(key, http.MethodGet, path, expires).Encode()},
+		{name: "head signed as get", method: http.MethodHead, query: SignGatewayPath(key, http.MethodGet, path, expires).Encode()},
+		{name: "post signed as put", method: http.MethodPost, query: SignGatewayPath(key, http.MethodPut, path, expires).Encode()},
+		{
+			name:   "upper case signature",
+			method: http.MethodGet,
+			query: "expires=" + SignGatewayPath(key, http.MethodGet, path, expires).Get(gatewayExpiresParam) +
+				"&signature=" + strings.ToUpper(SignGatewayPath(key, http.MethodGet, path, expires).Get(gatewaySignatureParam)),
+		},
+		{name: "missing", method: http.MethodGet, query: "", err: qerror.ParamsIsEmpty},
+		{name: "missing signature", method: http.MethodGet, query: "expires=1", err: qerror.ParamsIsEmpty},
+		{name: "invalid expires", method: http.MethodGet, query: "expires=x&signature=abc", err: qerror.InvalidParams},
+		{name: "other method", method: http.MethodPut, query: SignGatewayPath(key, http.MethodGet, path, expires).Encode(), err: qerror.SignatureNotMatch},
+		{name: "other key", method: http.MethodGet, query: SignGatewayPath("other", http.MethodGet, path, expires).Encode(), err: qerror.SignatureNotMatch},
+		{name: "other path", method: http.MethodGet, query: SignGatewayPath(key, http.MethodGet, path+"x", expires).Encode(), err: qerror.SignatureNotMatch},
+		{
+			name:   "expired",
+			method: http.MethodGet,
+			query:  SignGatewayPath(key, http.MethodGet, path, time.Now().Add(-time.Minute)).Encode(),
+			err:    qerror.ExpiredSignature,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, path+"?"+tt.query, nil)
+			err := g.authorize(r)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("authorize: %v", err)
+				}
+				return
+			}
+			if !isError(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSetCORSHeaders(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowed   []string
+		method    string
+		origin    string
+		preflight bool
+		allow     string
+		expose    bool
+	}{
+		{name: "no origin", allowed: []string{"*"}, method: http.MethodGet},
+		{name: "disabled", method: http.MethodGet, origin: "https://a.com"},
+		{name: "not allowed", allowed: []string{"https://b.com"}, method: http.MethodGet, origin: "https://a.com"},
+		{name: "allowed", allowed: []string{"https://b.com", "https://a.com"}, method: http.MethodGet, origin: "https://a.com", allow: "https://a.com", expose: true},
+		{name: "any", allowed: []string{"*"}, method: http.MethodPut, origin: "https://a.com", allow: "https://a.com", expose: true},
+		{name: "preflight", allowed: []string{"*"}, method: http.MethodOptions, origin: "https://a.com", preflight: true, allow: "https://a.com"},
+		{name: "preflight not allowed", allowed: []string{"https://b.com"}, method: http.MethodOptions, origin: "https://a.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &HTTPGateway{allowedOrigins: tt.allowed}
+			r := httptest.NewRequest(tt.method, "/", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == http.MethodOptions {
+				r.Header.Set("Access-Control-Request-Method", http.MethodPut)
+			}
+			w := httptest.NewRecorder()
+			if preflight := g.setCORSHeaders(w, r); preflight != tt.preflight {
+				t.Fatalf("preflight = %v, want %v", preflight, tt.preflight)
+			}
+			header := w.Header()
+			if got := header.Get("Access-Control-Allow-Origin"); got != tt.allow {
+				t.Fatalf("allow origin = %q, want %q", got, tt.allow)
+			}
+			if got := header.Get("Access-Control-Expose-Headers") != ""; got != tt.expose {
+				t.Fatalf("expose headers = %v, want %v", got, tt.expose)
+			}
+			if tt.origin != "" && header.Get("Vary") != "Origin" {
+				t.Fatalf("vary = %q", header.Get("Vary"))
+			}
+			if tt.preflight && (w.Code != http.StatusNoContent || header.Get("Access-Control-Allow-Methods") != corsAllowMethods) {
+				t.Fatalf("preflight response = %d, %v", w.Code, header)
+			}
+		})
+	}
+}
+
+// serveGateway sends the request signed with the method of request to g.
+func serveGateway(g *HTTPGateway, method string, body string, header map[string]string) *httptest.ResponseRecorder {
+	path := "/" + testSpaceId + "/" + testFileId + "/" + testVersion
+	query := SignGatewayPath(g.signingKey, method, path, time.Now().Add(time.Hour)).Encode()
+	r := httptest.NewRequest(method, path+"?"+query, strings.NewReader(body))
+	for key, value := range header {
+		r.Header.Set(key, value)
+	}
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, r)
+	return w
+}
+
+func TestHTTPGatewayTransfer(t *testing.T) {
+	setupStorage(t, nil)
+	g := NewHTTPGateway(glog.NewDefault().WithLevel(glog.FatalLevel), &config.HTTPGateway{SigningKey: "key"})
+	eTag := quoteETag(md5Hex("0123456789"))
+
+	w := serveGateway(g, http.MethodPut, "0123456789", nil)
+	if w.Code != http.StatusCreated || w.Header().Get("ETag") != eTag {
+		t.Fatalf("upload = %d, %s", w.Code, w.Body.String())
+	}
+	if w = serveGateway(g, http.MethodPut, "other", nil); w.Code != http.StatusConflict {
+		t.Fatalf("upload again = %d, %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		header map[string]string
+		status int
+		body   string
+		length string
+	}{
+		{name: "get", method: http.MethodGet, status: http.StatusOK, body: "0123456789", length: "10"},
+		{name: "head", method: http.MethodHead, status: http.StatusOK, length: "10"},
+		{name: "range", method: http.MethodGet, header: map[string]string{"Range": "bytes=2-4"}, status: http.StatusPartialContent, body: "234", length: "3"},
+		{
+			name:   "range if-range matched",
+			method: http.MethodGet,
+			header: map[string]string{"Range": "bytes=-2", "If-Range": eTag},
+			status: http.StatusPartialContent,
+			body:   "89",
+			length: "2",
+		},
+		{
+			name:   "range if-range mismatched",
+			method: http.MethodGet,
+			header: map[string]string{"Range": "bytes=-2", "If-Range": `"other"`},
+			status: http.StatusOK,
+			body:   "0123456789",
+			length: "10",
+		},
+		{name: "range not satisfiable", method: http.MethodGet, header: map[string]string{"Range": "bytes=10-"}, status: http.StatusRequestedRangeNotSatisfiable},
+		{name: "not modified", method: http.MethodGet, header: map[string]string{"If-None-Match": eTag}, status: http.StatusNotModified},
+		{name: "method not allowed", method: http.MethodDelete, status: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveGateway(g, tt.method, "", tt.header)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+			if tt.status >= http.StatusBadRequest {
+				return
+			}
+			if w.Body.String() != tt.body || w.Header().Get("Content-Length") != tt.length {
+				t.Fatalf("body = %q, length = %s", w.Body.String(), w.Header().Get("Content-Length"))
+			}
+			if w.Header().Get("ETag") != eTag {
+				t.Fatalf("eTag = %s, want %s", w.Header().Get("ETag"), eTag)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The sources of checksum mismatch in metrics.
+const (
+	mismatchSourceUpload  = "upload"
+	mismatchSourceChunk   = "chunk"
+	mismatchSourceScrub   = "scrub"
+	mismatchSourceMigrate = "migrate"
+)
+
+var (
+	transferBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "resourcemanager",
+		Subsystem: "storeio",
+		Name:      "transfer_bytes_total",
+		Help:      "The bytes of file data transferred with clients by direction (upload or download).",
+	}, []string{"direction"})
+
+	inflightStreams = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "resourcemanager",
+		Subsystem: "storeio",
+		Name:      "inflight_streams",
+		Help:      "The number of data streams in progress by method.",
+	}, []string{"method"})
+
+	uploadSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "resourcemanager",
+		Subsystem: "storeio",
+		Name:      "upload_size_bytes",
+		Help:      "The size of resource files that uploaded successfully.",
+		Buckets:   prometheus.ExponentialBuckets(1024, 4, 12),
+	})
+
+	checksumMismatches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "resourcemanager",
+		Subsystem: "storeio",
+		Name:      "checksum_mismatch_total",
+		Help:      "The number of checksum mismatches by source (upload, chunk, scrub or migrate).",
+	}, []string{"source"})
+)
+
+// trackStream increases the in-flight streams of method, and returns a func to decrease it.
+func trackStream(method string) func() {
+	gauge := inflightStreams.WithLabelValues(method)
+	gauge.Inc()
+	return gauge.Dec
+}
+
+// observeUpload records a resource file uploaded successfully.
+func observeUpload(size int64) {
+	uploadSize.Observe(float64(size))
+}
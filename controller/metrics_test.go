@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTransferMetrics(t *testing.T) {
+	x := new(StoreIo)
+	setupStorage(t, nil)
+	// The metrics are shared by tests, so compare the changes.
+	upload := testutil.ToFloat64(transferBytes.WithLabelValues("upload"))
+	download := testutil.ToFloat64(transferBytes.WithLabelValues("download"))
+	mismatches := testutil.ToFloat64(checksumMismatches.WithLabelValues(mismatchSourceUpload))
+
+	if _, err := writeFileData(x, testVersion, "hello", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	md := metadata.Pairs(checksumMD5MetadataKey, strings.Repeat("0", 32))
+	if _, err := writeFileData(x, "0000000000000002", "world", md); err == nil {
+		t.Fatal("expected error for checksum mismatch")
+	}
+	if _, err := readFileData(x, testVersion, 1, 2); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{name: "upload bytes", got: testutil.ToFloat64(transferBytes.WithLabelValues("upload")) - upload, want: 5},
+		{name: "download bytes", got: testutil.ToFloat64(transferBytes.WithLabelValues("download")) - download, want: 2},
+		{name: "upload mismatches", got: testutil.ToFloat64(checksumMismatches.WithLabelValues(mismatchSourceUpload)) - mismatches, want: 1},
+		{name: "in-flight writes", got: testutil.ToFloat64(inflightStreams.WithLabelValues("WriteFileData")), want: 0},
+		{name: "in-flight reads", got: testutil.ToFloat64(inflightStreams.WithLabelValues("ReadFileData")), want: 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Fatalf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestTrackStream(t *testing.T) {
+	const method = "TestTrackStream"
+	done1 := trackStream(method)
+	done2 := trackStream(method)
+	if got := testutil.ToFloat64(inflightStreams.WithLabelValues(method)); got != 2 {
+		t.Fatalf("in-flight = %v, want 2", got)
+	}
+	done1()
+	done2()
+	if got := testutil.ToFloat64(inflightStreams.WithLabelValues(method)); got != 0 {
+		t.Fatalf("in-flight = %v, want 0", got)
+	}
+}
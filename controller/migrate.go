@@ -0,0 +1,455 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataWorkbench/common/lib/storeio"
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/gproto/xgo/types/pbmodel"
+	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+)
+
+// migrateProgressInterval is the interval to log the progress of migration.
+const migrateProgressInterval = 10 * time.Second
+
+// The states of migration.
+const (
+	MigrationStateRunning   = "running"
+	MigrationStateCompleted = "completed"
+	MigrationStateFailed    = "failed"
+	MigrationStateCanceled  = "canceled"
+)
+
+// migrateProgress is the counters of MigrationProgress that updated atomically.
+type migrateProgress struct {
+	spaces     int64
+	spacesDone int64
+	files      int64
+	copied     int64
+	skipped    int64
+	failed     int64
+	bytes      int64
+}
+
+// Migrator copies the workspaces from a storage backend to another. Each file is verified with
+// the digest stored at upload while copying, and re-read from target after copied. The files that
+// already exist in target with the same size and digest are skipped, so an interrupted migration can be resumed
+// by running again. The files in source are kept, remove them after the routing is switched to target.
+type Migrator struct {
+	source      string
+	target      string
+	src         fileio.FileIO
+	dst         fileio.FileIO
+	spaceIds    []string
+	concurrency int
+
+	progress migrateProgress
+}
+
+// NewMigrator creates a Migrator between the named storage backends. All workspaces in
+// source are migrated if spaceIds is empty, concurrency <= 0 means use the config.
+func NewMigrator(source, target string, spaceIds []string, concurrency int) (*Migrator, error) {
+	src, ok := options.Storages.Get(source)
+	if !ok {
+		return nil, qerror.InvalidParams.Format("source")
+	}
+	dst, ok := options.Storages.Get(target)
+	if !ok || target == source {
+		return nil, qerror.InvalidParams.Format("target")
+	}
+	if concurrency <= 0 {
+		concurrency = options.Config.Storage.MigrateConcurrency
+	}
+	m := &Migrator{
+		source:      source,
+		target:      target,
+		src:         src,
+		dst:         dst,
+		spaceIds:    spaceIds,
+		concurrency: concurrency,
+	}
+	return m, nil
+}
+
+// Progress returns a snapshot of the progress.
+func (m *Migrator) Progress() *pbstoreio.MigrationProgress {
+	return &pbstoreio.MigrationProgress{
+		Spaces:     atomic.LoadInt64(&m.progress.spaces),
+		SpacesDone: atomic.LoadInt64(&m.progress.spacesDone),
+		Files:      atomic.LoadInt64(&m.progress.files),
+		Copied:     atomic.LoadInt64(&m.progress.copied),
+		Skipped:    atomic.LoadInt64(&m.progress.skipped),
+		Failed:     atomic.LoadInt64(&m.progress.failed),
+		Bytes:      atomic.LoadInt64(&m.progress.bytes),
+	}
+}
+
+// Run copies all files of the workspaces, returns error if any file failed to copy or ctx done.
+func (m *Migrator) Run(ctx context.Context) error {
+	lg := glog.FromContext(ctx)
+
+	spaceIds := m.spaceIds
+	if len(spaceIds) == 0 {
+		var err error
+		if spaceIds, err = m.listWorkspaces(ctx); err != nil {
+			return err
+		}
+	}
+	atomic.StoreInt64(&m.progress.spaces, int64(len(spaceIds)))
+	lg.Info().Msg("migration: start").String("source", m.source).String("target", m.target).
+		Int("spaces", len(spaceIds)).Int("concurrency", m.concurrency).Fire()
+
+	done := make(chan struct{})
+	defer close(done)
+	go m.logProgress(ctx, done)
+
+	for _, spaceId := range spaceIds {
+		if err := m.migrateWorkspace(ctx, spaceId); err != nil {
+			return err
+		}
+		atomic.AddInt64(&m.progress.spacesDone, 1)
+	}
+
+	p := m.Progress()
+	lg.Info().Msg("migration: done").Int64("files", p.Files).Int64("copied", p.Copied).
+		Int64("skipped", p.Skipped).Int64("failed", p.Failed).Int64("bytes", p.Bytes).Fire()
+	if p.Failed > 0 {
+		return fmt.Errorf("%d files failed to migrate, run again to retry", p.Failed)
+	}
+	return nil
+}
+
+func (m *Migrator) logProgress(ctx context.Context, done chan struct{}) {
+	lg := glog.FromContext(ctx)
+	ticker := time.NewTicker(migrateProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		p := m.Progress()
+		lg.Info().Msg("migration: progress").Int64("spaces", p.Spaces).Int64("spacesDone", p.SpacesDone).
+			Int64("files", p.Files).Int64("copied", p.Copied).Int64("skipped", p.Skipped).
+			Int64("failed", p.Failed).Int64("bytes", p.Bytes).Fire()
+	}
+}
+
+// listWorkspaces returns the ids of all workspaces in source.
+func (m *Migrator) listWorkspaces(ctx context.Context) ([]string, error) {
+	infos, err := m.src.List(ctx, resourceRootDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	spaceIds := make([]string, 0, len(infos))
+	for _, info := range infos {
+		name := path.Base(info.Name)
+		if info.IsDir && !strings.HasPrefix(name, ".") {
+			spaceIds = append(spaceIds, name)
+		}
+	}
+	return spaceIds, nil
+}
+
+// migrateWorkspace copies all files in workspace dir concurrently, includes the digests and trash.
+// The staging files and upload sessions are ignored, they are only meaningful to the source.
+func (m *Migrator) migrateWorkspace(ctx context.Context, spaceId string) error {
+	lg := glog.FromContext(ctx)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, m.concurrency)
+	err := m.src.Walk(ctx, storeio.GenerateWorkspaceDir(spaceId), func(name string, info *fileio.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir {
+			if strings.HasPrefix(path.Base(name), uploadSessionDirPrefix) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fileio.IsStagingFile(name) {
+			return nil
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		atomic.AddInt64(&m.progress.files, 1)
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := m.copyFile(ctx, info); err != nil {
+				atomic.AddInt64(&m.progress.failed, 1)
+				lg.Error().Msg("migration: copy file failed").String("name", info.Name).Error("error", err).Fire()
+			}
+		}()
+		return nil
+	})
+	wg.Wait()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return ctx.Err()
+}
+
+// copyFile copies the file to target, and verifies it with the digest stored in source and re-read from target.
+func (m *Migrator) copyFile(ctx context.Context, info *fileio.FileInfo) error {
+	name := info.Name
+
+	// The resource file version has a digest sidecar in the same dir. The files in hidden
+	// dir, such as digests and trash, have no digest.
+	var expected *fileio.Digest
+	if dir := path.Dir(name); !strings.HasPrefix(path.Base(dir), ".") {
+		expected = new(fileio.Digest)
+		if err := readJSONFile(ctx, m.src, dir+"/"+digestDirName+"/"+path.Base(name), expected); err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
+			expected = nil
+		}
+	}
+
+	dstInfo, err := m.dst.Stat(ctx, name)
+	if err == nil {
+		if dstInfo.Size != info.Size {
+			return fmt.Errorf("file exists in target with size %d, expected %d", dstInfo.Size, info.Size)
+		}
+		if err = m.verifyExisting(ctx, name, expected); err != nil {
+			return err
+		}
+		atomic.AddInt64(&m.progress.skipped, 1)
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	reader, err := m.src.OpenForRead(ctx, name)
+	if err != nil {
+		return err
+	}
+	digestReader := fileio.NewDigestReader(reader, expected)
+	defer func() {
+		_ = digestReader.Close()
+	}()
+	if err = m.dst.MkdirAll(ctx, path.Dir(name), 0777); err != nil {
+		return err
+	}
+	if _, err = m.dst.CreateAndWrite(ctx, name, digestReader); err != nil {
+		// The file is written to target concurrently, such as uploaded in dual-read mode.
+		if os.IsExist(err) {
+			atomic.AddInt64(&m.progress.skipped, 1)
+			return nil
+		}
+		if digestReader.Mismatched() {
+			checksumMismatches.WithLabelValues(mismatchSourceMigrate).Inc()
+		}
+		return err
+	}
+
+	copied, err := m.dst.OpenForRead(ctx, name)
+	if err != nil {
+		return err
+	}
+	digest, size, err := fileio.ComputeDigest(copied)
+	_ = copied.Close()
+	if err != nil {
+		return err
+	}
+	if size != digestReader.Size() || !digest.Match(digestReader.Digest()) {
+		// Remove it so the next run copies again.
+		_ = m.dst.Remove(ctx, name)
+		checksumMismatches.WithLabelValues(mismatchSourceMigrate).Inc()
+		return fileio.ErrChecksumMismatch
+	}
+	atomic.AddInt64(&m.progress.copied, 1)
+	atomic.AddInt64(&m.progress.bytes, size)
+	return nil
+}
+
+// verifyExisting compares the digest of file that exists in target with the digest stored in source,
+// or computed from source if not stored.
+func (m *Migrator) verifyExisting(ctx context.Context, name string, expected *fileio.Digest) error {
+	if expected == nil {
+		reader, err := m.src.OpenForRead(ctx, name)
+		if err != nil {
+			return err
+		}
+		expected, _, err = fileio.ComputeDigest(reader)
+		_ = reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+	reader, err := m.dst.OpenForRead(ctx, name)
+	if err != nil {
+		return err
+	}
+	digest, _, err := fileio.ComputeDigest(reader)
+	_ = reader.Close()
+	if err != nil {
+		return err
+	}
+	if !digest.Match(expected) {
+		checksumMismatches.WithLabelValues(mismatchSourceMigrate).Inc()
+		return fmt.Errorf("file exists in target with different digest: %w", fileio.ErrChecksumMismatch)
+	}
+	return nil
+}
+
+// migrationJob is the migration that started by StoreIo.StartMigration.
+type migrationJob struct {
+	migrator *Migrator
+	cancel   context.CancelFunc
+
+	mu       sync.Mutex
+	state    string
+	started  time.Time
+	finished time.Time
+	err      error
+}
+
+func (j *migrationJob) status() *pbstoreio.MigrationStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	status := &pbstoreio.MigrationStatus{
+		Source:   j.migrator.source,
+		Target:   j.migrator.target,
+		State:    j.state,
+		Started:  j.started.Unix(),
+		Progress: j.migrator.Progress(),
+	}
+	if !j.finished.IsZero() {
+		status.Finished = j.finished.Unix()
+	}
+	if j.err != nil {
+		status.Error = j.err.Error()
+	}
+	return status
+}
+
+var (
+	migrationMu sync.Mutex
+	// migration is the latest migration in this instance.
+	migration *migrationJob
+)
+
+// enableDualRead routes the workspaces to target, and makes the reads fall back to source.
+func enableDualRead(source, target string, spaceIds []string) error {
+	if err := options.Storages.SetFallback(target, source); err != nil {
+		return err
+	}
+	if len(spaceIds) == 0 {
+		return options.Storages.Reroute(source, target)
+	}
+	for _, spaceId := range spaceIds {
+		if err := options.Storages.SetRoute(spaceId, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartMigration starts to migrate the workspaces between storage backends in background.
+// Only one migration can run at the same time in an instance.
+//
+// The migration and the dual-read routing are kept in memory of this instance, they are not
+// shared with the other replicas and lost after restart. So the dual-read is only suitable for
+// the deployment with a single replica, otherwise update the routing in config of all replicas.
+func (x *StoreIo) StartMigration(ctx context.Context, req *pbstoreio.StartMigrationRequest) (*pbstoreio.MigrationStatus, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	lg := glog.FromContext(ctx)
+
+	migrationMu.Lock()
+	defer migrationMu.Unlock()
+	if migration != nil && migration.status().State == MigrationStateRunning {
+		return nil, qerror.InvalidRequest.Format("another migration is running")
+	}
+	migrator, err := NewMigrator(req.Source, req.Target, req.SpaceIds, int(req.Concurrency))
+	if err != nil {
+		return nil, err
+	}
+	if req.DualRead {
+		if err = enableDualRead(req.Source, req.Target, req.SpaceIds); err != nil {
+			return nil, err
+		}
+		lg.Info().Msg("migration: dual-read enabled").String("source", req.Source).String("target", req.Target).Fire()
+	}
+
+	// The migration outlives the request, so it logs by a clone of the request logger.
+	runLg := lg.Clone()
+	runCtx, cancel := context.WithCancel(glog.WithContext(context.Background(), runLg))
+	job := &migrationJob{migrator: migrator, cancel: cancel, state: MigrationStateRunning, started: time.Now()}
+	migration = job
+
+	go func() {
+		defer func() {
+			cancel()
+			_ = runLg.Close()
+		}()
+		err := migrator.Run(runCtx)
+
+		job.mu.Lock()
+		defer job.mu.Unlock()
+		job.finished = time.Now()
+		job.err = err
+		switch {
+		case err == nil:
+			job.state = MigrationStateCompleted
+		case runCtx.Err() != nil:
+			job.state = MigrationStateCanceled
+		default:
+			job.state = MigrationStateFailed
+		}
+		if err == nil && req.DualRead {
+			// All files are in target now. The routing changes are not persisted, update the config to keep them.
+			_ = options.Storages.SetFallback(req.Target, "")
+			runLg.Info().Msg("migration: dual-read disabled, update routing in config to keep the workspaces in target").
+				String("target", req.Target).Fire()
+		}
+	}()
+	return job.status(), nil
+}
+
+// GetMigration returns the status of the latest migration in this instance.
+func (x *StoreIo) GetMigration(ctx context.Context, _ *pbmodel.EmptyStruct) (*pbstoreio.MigrationStatus, error) {
+	migrationMu.Lock()
+	defer migrationMu.Unlock()
+	if migration == nil {
+		return nil, qerror.ResourceNotExists.Format("migration")
+	}
+	return migration.status(), nil
+}
+
+// CancelMigration cancels the running migration. It can be resumed by starting again.
+func (x *StoreIo) CancelMigration(ctx context.Context, _ *pbmodel.EmptyStruct) (*pbstoreio.MigrationStatus, error) {
+	migrationMu.Lock()
+	defer migrationMu.Unlock()
+	if migration == nil {
+		return nil, qerror.ResourceNotExists.Format("migration")
+	}
+	migration.cancel()
+	return migration.status(), nil
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataWorkbench/gproto/xgo/types/pbmodel"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+)
+
+const testTargetStorage = "target"
+
+// setupMigration sets up the default storage as source with a resource file uploaded and
+// an upload session in progress, and registers an empty memory storage as target.
+func setupMigration(t *testing.T) (x *StoreIo, src, dst *fileio.Memory) {
+	t.Helper()
+	x = new(StoreIo)
+	src = setupStorage(t, nil)
+	dst = addStorage(t, testTargetStorage)
+	if _, err := writeFileData(x, testVersion, "hello", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err := x.InitUploadSession(testContext(), &pbstoreio.InitUploadSessionRequest{
+		SpaceId: testSpaceId, FileId: testFileId, Version: "0000000000000002", Size: 5,
+	})
+	if err != nil {
+		t.Fatalf("init upload session: %v", err)
+	}
+	return x, src, dst
+}
+
+func TestMigratorRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, x *StoreIo, dst *fileio.Memory)
+		failed  bool
+		copied  int64
+		skipped int64
+	}{
+		{
+			name: "copy",
+			// The file and its digest.
+			copied: 2,
+		},
+		{
+			name: "resume",
+			setup: func(t *testing.T, x *StoreIo, dst *fileio.Memory) {
+				m, err := NewMigrator(config.DefaultStorageName, testTargetStorage, nil, 0)
+				if err != nil {
+					t.Fatalf("new migrator: %v", err)
+				}
+				if err = m.Run(testContext()); err != nil {
+					t.Fatalf("run: %v", err)
+				}
+			},
+			skipped: 2,
+		},
+		{
+			name: "different in target",
+			setup: func(t *testing.T, x *StoreIo, dst *fileio.Memory) {
+				filePath := x.generateResourceFilePath(testSpaceId, testFileId, testVersion)
+				if err := dst.MkdirAll(testContext(), x.generateResourceFileDir(testSpaceId, testFileId), 0777); err != nil {
+					t.Fatalf("mkdir: %v", err)
+				}
+				if _, err := dst.CreateAndWrite(testContext(), filePath, io.NopCloser(strings.NewReader("hellO"))); err != nil {
+					t.Fatalf("create: %v", err)
+				}
+			},
+			failed: true,
+			copied: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, _, dst := setupMigration(t)
+			if tt.setup != nil {
+				tt.setup(t, x, dst)
+			}
+
+			m, err := NewMigrator(config.DefaultStorageName, testTargetStorage, nil, 0)
+			if err != nil {
+				t.Fatalf("new migrator: %v", err)
+			}
+			err = m.Run(testContext())
+			if (err != nil) != tt.failed {
+				t.Fatalf("run error = %v, want failed %v", err, tt.failed)
+			}
+			p := m.Progress()
+			if p.Spaces != 1 || p.Files != 2 || p.Copied != tt.copied || p.Skipped != tt.skipped {
+				t.Fatalf("progress = %v", p)
+			}
+			if tt.failed {
+				return
+			}
+			// The upload session is not migrated.
+			files, err := dst.List(testContext(), x.generateResourceFileDir(testSpaceId, testFileId))
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			for _, info := range files {
+				if strings.Contains(info.Name, uploadSessionDirPrefix) {
+					t.Fatalf("the upload session is migrated: %s", info.Name)
+				}
+			}
+			if got := readStorageFile(t, dst, x.generateResourceFilePath(testSpaceId, testFileId, testVersion)); got != "hello" {
+				t.Fatalf("data in target = %q", got)
+			}
+		})
+	}
+}
+
+func TestNewMigratorInvalid(t *testing.T) {
+	setupMigration(t)
+	tests := []struct {
+		source string
+		target string
+	}{
+		{source: "none", target: testTargetStorage},
+		{source: config.DefaultStorageName, target: "none"},
+		{source: config.DefaultStorageName, target: config.DefaultStorageName},
+	}
+	for _, tt := range tests {
+		if _, err := NewMigrator(tt.source, tt.target, nil, 0); err == nil {
+			t.Errorf("NewMigrator(%s, %s) expected error", tt.source, tt.target)
+		}
+	}
+}
+
+func TestStartMigrationDualRead(t *testing.T) {
+	x, _, dst := setupMigration(t)
+	ctx := testContext()
+
+	status, err := x.StartMigration(ctx, &pbstoreio.StartMigrationRequest{
+		Source: config.DefaultStorageName, Target: testTargetStorage, SpaceIds: []string{testSpaceId}, DualRead: true,
+	})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if status.Source != config.DefaultStorageName || status.Target != testTargetStorage {
+		t.Fatalf("status = %v", status)
+	}
+	// The workspace is routed to target at once.
+	if name, _ := options.Storages.Route(testSpaceId); name != testTargetStorage {
+		t.Fatalf("route = %s", name)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for status.State == MigrationStateRunning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if status, err = x.GetMigration(ctx, &pbmodel.EmptyStruct{}); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	}
+	if status.State != MigrationStateCompleted || status.Progress.Copied != 2 || status.Finished == 0 {
+		t.Fatalf("status = %v", status)
+	}
+	// The fallback is disabled after completed.
+	if _, fs := options.Storages.Route(testSpaceId); fs != fileio.FileIO(dst) {
+		t.Fatalf("route = %T, want target", fs)
+	}
+}
+
+// readStorageFile returns all data of the file name in fs.
+func readStorageFile(t *testing.T, fs fileio.FileIO, name string) string {
+	t.Helper()
+	reader, err := fs.OpenForRead(testContext(), name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
@@ -0,0 +1,490 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataWorkbench/common/lib/storeio"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+)
+
+// The scrubber saves progress and quarantines the mismatched files in hidden directories under
+// resourceRootDir of each storage backend, so it can be resumed by any instance that shares the storage.
+// The instances that share the storage take turns by the lease, only the lease holder runs the pass
+// and writes the checkpoint.
+var (
+	scrubberCheckpointPath = resourceRootDir + "/.scrubber/checkpoint.json"
+	scrubberLeasePath      = resourceRootDir + "/.scrubber/lease.json"
+	scrubberQuarantineDir  = resourceRootDir + "/.quarantine"
+)
+
+// errScrubLeaseLost is returned if the lease is taken over by another instance during the pass.
+var errScrubLeaseLost = errors.New("scrubber: lease lost")
+
+// ScrubResult is the summary of a scrub pass.
+type ScrubResult struct {
+	// The number of resource files that verified.
+	Scanned int64 `json:"scanned"`
+	// The bytes of data that read.
+	Bytes int64 `json:"bytes"`
+	// The number of resource files that the digest mismatched.
+	Mismatched int64 `json:"mismatched"`
+	// The number of mismatched files that moved to quarantine directory.
+	Quarantined int64 `json:"quarantined"`
+	// The number of resource files that have no digest stored.
+	NoDigest int64 `json:"no_digest"`
+	// The number of resource files that failed to verify.
+	Failed int64 `json:"failed"`
+}
+
+func (r *ScrubResult) add(o *ScrubResult) {
+	r.Scanned += o.Scanned
+	r.Bytes += o.Bytes
+	r.Mismatched += o.Mismatched
+	r.Quarantined += o.Quarantined
+	r.NoDigest += o.NoDigest
+	r.Failed += o.Failed
+}
+
+// scrubCheckpoint is the progress of scrub pass.
+type scrubCheckpoint struct {
+	// The last verified file, in format `<spaceId>/<fileId>/<version>`.
+	Position string `json:"position"`
+	// The start time of scrub pass, timestamp in seconds.
+	Started int64 `json:"started"`
+	// The result of scrub pass so far.
+	Result ScrubResult `json:"result"`
+}
+
+// scrubLease is held by the instance that runs the scrub pass of a storage backend. It is
+// renewed on each checkpoint, and can be taken over by others after expired.
+type scrubLease struct {
+	// The instance that holds the lease.
+	Owner string `json:"owner"`
+	// Timestamp in seconds.
+	Expired int64 `json:"expired"`
+}
+
+// rateLimiter limits the average bytes per second. It is not safe for concurrent use.
+type rateLimiter struct {
+	rate  int64
+	start time.Time
+	bytes int64
+}
+
+func newRateLimiter(rate int64) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &rateLimiter{rate: rate, start: time.Now()}
+}
+
+// wait blocks until the n bytes is allowed.
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	l.bytes += int64(n)
+	expected := time.Duration(float64(l.bytes) / float64(l.rate) * float64(time.Second))
+	d := expected - time.Since(l.start)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reader returns a reader that limited by l. Returns r directly if l is nil.
+func (l *rateLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &rateLimitedReader{ctx: ctx, reader: r, limiter: l}
+}
+
+type rateLimitedReader struct {
+	ctx     context.Context
+	reader  io.Reader
+	limiter *rateLimiter
+}
+
+func (r *rateLimitedReader) Read(p []byte) (n int, err error) {
+	n, err = r.reader.Read(p)
+	if n > 0 {
+		if werr := r.limiter.wait(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+// Scrubber walks all workspaces in all storage backends, re-reads each resource file, and compares its digest
+// with the digest stored at upload to find the silent corruption.
+type Scrubber struct {
+	cfg       *config.Scrubber
+	x         *StoreIo
+	owner     string // The lease owner, unique for each Scrubber.
+	rateLimit int64  // In MiB per second, can be changed by SetRateLimit.
+}
+
+// NewScrubber creates a Scrubber with the config.
+func NewScrubber(cfg *config.Scrubber) *Scrubber {
+	hostname, _ := os.Hostname()
+	owner := fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+	return &Scrubber{cfg: cfg, x: &StoreIo{}, owner: owner, rateLimit: cfg.RateLimit}
+}
+
+// SetRateLimit changes the max MiB of data to read per second, 0 means unlimited.
+// It takes effect on the running pass.
+func (s *Scrubber) SetRateLimit(rate int64) {
+	atomic.StoreInt64(&s.rateLimit, rate)
+}
+
+// Run runs scrub pass continuously until ctx done.
+func (s *Scrubber) Run(ctx context.Context) {
+	lg := glog.FromContext(ctx)
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			lg.Error().Msg("scrubber: scrub pass failed").Error("error", err).Fire()
+		}
+		timer := time.NewTimer(s.cfg.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// RunOnce runs a scrub pass over all storage backends, and returns the total result.
+// The pass of each backend resumes from its last checkpoint if exists, and is skipped
+// if another instance holds the lease.
+func (s *Scrubber) RunOnce(ctx context.Context) (*ScrubResult, error) {
+	total := new(ScrubResult)
+	for _, name := range options.Storages.Names() {
+		fs, _ := options.Storages.Get(name)
+		result, err := s.runOnce(ctx, name, fs)
+		total.add(result)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (s *Scrubber) runOnce(ctx context.Context, storage string, fs fileio.FileIO) (*ScrubResult, error) {
+	lg := glog.FromContext(ctx).Clone()
+	defer func() {
+		_ = lg.Close()
+	}()
+	lg.WithFields().AddString("storage", storage)
+	ctx = glog.WithContext(ctx, lg)
+
+	held, err := s.acquireLease(ctx, fs)
+	if err != nil {
+		lg.Error().Msg("scrubber: acquire lease failed").Error("error", err).Fire()
+		return new(ScrubResult), err
+	}
+	if !held {
+		lg.Info().Msg("scrubber: lease held by another instance, skip scrub pass").Fire()
+		return new(ScrubResult), nil
+	}
+	defer func() {
+		if err := s.releaseLease(ctx, fs); err != nil {
+			lg.Warn().Msg("scrubber: release lease failed").Error("error", err).Fire()
+		}
+	}()
+
+	cp, err := s.loadCheckpoint(ctx, fs)
+	if err != nil {
+		lg.Warn().Msg("scrubber: load checkpoint failed, start a new pass").Error("error", err).Fire()
+		cp = nil
+	}
+	saved := cp != nil
+	if cp == nil {
+		cp = &scrubCheckpoint{Started: time.Now().Unix()}
+	}
+	lg.Info().Msg("scrubber: start scrub pass").String("position", cp.Position).Fire()
+
+	rate := atomic.LoadInt64(&s.rateLimit)
+	limiter := newRateLimiter(rate * 1024 * 1024)
+	lastSaved := time.Now()
+
+	err = s.walk(ctx, fs, cp.Position, func(spaceId, fileId, version string) error {
+		if r := atomic.LoadInt64(&s.rateLimit); r != rate {
+			lg.Info().Msg("scrubber: rate limit changed").Int64("from", rate).Int64("to", r).Fire()
+			rate = r
+			limiter = newRateLimiter(rate * 1024 * 1024)
+		}
+		s.verify(ctx, fs, spaceId, fileId, version, limiter, &cp.Result)
+		cp.Position = spaceId + "/" + fileId + "/" + version
+		if time.Since(lastSaved) >= s.cfg.CheckpointInterval {
+			if err := s.renewLease(ctx, fs); err != nil {
+				return err
+			}
+			if err := s.saveCheckpoint(ctx, fs, cp); err != nil {
+				lg.Warn().Msg("scrubber: save checkpoint failed").Error("error", err).Fire()
+			} else {
+				saved = true
+			}
+			lastSaved = time.Now()
+		}
+		return ctx.Err()
+	})
+	if err == errScrubLeaseLost {
+		// The new holder resumes from the last checkpoint.
+		lg.Warn().Msg("scrubber: lease taken over by another instance, stop scrub pass").Fire()
+		return &cp.Result, nil
+	}
+	if err != nil {
+		// Save the progress to resume later.
+		_ = s.saveCheckpoint(ctx, fs, cp)
+		return &cp.Result, err
+	}
+
+	// The pass is complete, the next pass starts from beginning.
+	if saved {
+		if err = fs.Remove(ctx, scrubberCheckpointPath); err != nil && !os.IsNotExist(err) {
+			lg.Warn().Msg("scrubber: remove checkpoint failed").Error("error", err).Fire()
+		}
+	}
+	lg.Info().Msg("scrubber: scrub pass done").Int64("scanned", cp.Result.Scanned).
+		Int64("bytes", cp.Result.Bytes).Int64("mismatched", cp.Result.Mismatched).
+		Int64("quarantined", cp.Result.Quarantined).Int64("noDigest", cp.Result.NoDigest).
+		Int64("failed", cp.Result.Failed).Fire()
+	return &cp.Result, nil
+}
+
+// walk calls fn for each resource file in lexical order, skips the files not after position.
+func (s *Scrubber) walk(ctx context.Context, fs fileio.FileIO, position string, fn func(spaceId, fileId, version string) error) error {
+	spaces, err := s.listNames(ctx, fs, resourceRootDir, true)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, spaceId := range spaces {
+		if position != "" && spaceId < strings.SplitN(position, "/", 2)[0] {
+			continue
+		}
+		fileIds, err := s.listNames(ctx, fs, storeio.GenerateWorkspaceDir(spaceId), true)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		for _, fileId := range fileIds {
+			versions, err := s.listNames(ctx, fs, s.x.generateResourceFileDir(spaceId, fileId), false)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return err
+			}
+			for _, version := range versions {
+				if spaceId+"/"+fileId+"/"+version <= position {
+					continue
+				}
+				if err = fn(spaceId, fileId, version); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// listNames returns the names of directories in dir if isDir is true, or files otherwise.
+// The hidden entries and staging files are ignored.
+func (s *Scrubber) listNames(ctx context.Context, fs fileio.FileIO, dir string, isDir bool) ([]string, error) {
+	infos, err := fs.List(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		name := path.Base(info.Name)
+		if info.IsDir != isDir || strings.HasPrefix(name, ".") || fileio.IsStagingFile(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (s *Scrubber) verify(ctx context.Context, fs fileio.FileIO, spaceId, fileId, version string, limiter *rateLimiter, result *ScrubResult) {
+	lg := glog.FromContext(ctx).Clone()
+	defer func() {
+		_ = lg.Close()
+	}()
+	fields := lg.WithFields()
+	fields.AddString("spaceId", spaceId)
+	fields.AddString("fileId", fileId)
+	fields.AddString("version", version)
+
+	reply, err := s.x.verifyFileData(ctx, fs, spaceId, fileId, version, limiter)
+	if err != nil {
+		// The file may be deleted during scrub.
+		if os.IsNotExist(err) {
+			return
+		}
+		result.Failed++
+		lg.Error().Msg("scrubber: verify file data failed").Error("error", err).Fire()
+		return
+	}
+	result.Scanned++
+	result.Bytes += reply.Size
+
+	if reply.StoredMd5 == "" && reply.StoredSha256 == "" {
+		result.NoDigest++
+		lg.Debug().Msg("scrubber: no digest stored for file").Fire()
+		return
+	}
+	if reply.Matched {
+		return
+	}
+
+	result.Mismatched++
+	checksumMismatches.WithLabelValues(mismatchSourceScrub).Inc()
+	lg.Error().Msg("scrubber: the digest of file data mismatch").
+		String("md5", reply.Md5).String("storedMd5", reply.StoredMd5).
+		String("sha256", reply.Sha256).String("storedSha256", reply.StoredSha256).Fire()
+	if !s.cfg.Quarantine {
+		return
+	}
+	if err = s.quarantine(ctx, fs, spaceId, fileId, version); err != nil {
+		lg.Error().Msg("scrubber: quarantine file failed").Error("error", err).Fire()
+		return
+	}
+	result.Quarantined++
+}
+
+// quarantine moves the resource file and its digest to quarantine directory, so it can not be read anymore.
+func (s *Scrubber) quarantine(ctx context.Context, fs fileio.FileIO, spaceId, fileId, version string) error {
+	dir := scrubberQuarantineDir + "/" + spaceId + "/" + fileId
+	if err := fs.MkdirAll(ctx, dir, 0777); err != nil {
+		return err
+	}
+	name := dir + "/" + version + "-" + strconv.FormatInt(time.Now().Unix(), 10)
+	if err := fs.Rename(ctx, s.x.generateResourceFilePath(spaceId, fileId, version), name); err != nil {
+		return err
+	}
+	err := fs.Rename(ctx, s.x.generateDigestPath(spaceId, fileId, version), name+".digest")
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	glog.FromContext(ctx).Warn().Msg("scrubber: file moved to quarantine").String("name", name).Fire()
+	return nil
+}
+
+// loadCheckpoint returns nil if no checkpoint.
+func (s *Scrubber) loadCheckpoint(ctx context.Context, fs fileio.FileIO) (*scrubCheckpoint, error) {
+	cp := new(scrubCheckpoint)
+	if err := readJSONFile(ctx, fs, scrubberCheckpointPath, cp); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return cp, nil
+}
+
+func (s *Scrubber) saveCheckpoint(ctx context.Context, fs fileio.FileIO, cp *scrubCheckpoint) error {
+	if err := fs.MkdirAll(ctx, path.Dir(scrubberCheckpointPath), 0777); err != nil {
+		return err
+	}
+	if err := fs.Remove(ctx, scrubberCheckpointPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return writeJSONFile(ctx, fs, scrubberCheckpointPath, cp)
+}
+
+// acquireLease returns false if the lease is held by another instance and not expired.
+func (s *Scrubber) acquireLease(ctx context.Context, fs fileio.FileIO) (bool, error) {
+	if err := fs.MkdirAll(ctx, path.Dir(scrubberLeasePath), 0777); err != nil {
+		return false, err
+	}
+	lease := &scrubLease{Owner: s.owner, Expired: time.Now().Add(s.cfg.LeaseTTL).Unix()}
+	err := writeJSONFile(ctx, fs, scrubberLeasePath, lease)
+	if err == nil || !os.IsExist(err) {
+		return err == nil, err
+	}
+
+	current := new(scrubLease)
+	if err = readJSONFile(ctx, fs, scrubberLeasePath, current); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if err == nil && current.Owner != s.owner && time.Now().Unix() < current.Expired {
+		return false, nil
+	}
+	// The lease is expired or released just now, take it over. The create fails if
+	// another instance takes it over first.
+	if err = fs.Remove(ctx, scrubberLeasePath); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	err = writeJSONFile(ctx, fs, scrubberLeasePath, lease)
+	if os.IsExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// renewLease extends the lease, returns errScrubLeaseLost if it is held by another instance.
+func (s *Scrubber) renewLease(ctx context.Context, fs fileio.FileIO) error {
+	current := new(scrubLease)
+	if err := readJSONFile(ctx, fs, scrubberLeasePath, current); err != nil {
+		if os.IsNotExist(err) {
+			return errScrubLeaseLost
+		}
+		return err
+	}
+	if current.Owner != s.owner {
+		return errScrubLeaseLost
+	}
+	if err := fs.Remove(ctx, scrubberLeasePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	lease := &scrubLease{Owner: s.owner, Expired: time.Now().Add(s.cfg.LeaseTTL).Unix()}
+	if err := writeJSONFile(ctx, fs, scrubberLeasePath, lease); err != nil {
+		if os.IsExist(err) {
+			return errScrubLeaseLost
+		}
+		return err
+	}
+	return nil
+}
+
+// releaseLease removes the lease if it is held by this instance.
+func (s *Scrubber) releaseLease(ctx context.Context, fs fileio.FileIO) error {
+	current := new(scrubLease)
+	if err := readJSONFile(ctx, fs, scrubberLeasePath, current); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if current.Owner != s.owner {
+		return nil
+	}
+	if err := fs.Remove(ctx, scrubberLeasePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
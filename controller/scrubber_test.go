@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+)
+
+func newTestScrubber(quarantine bool) *Scrubber {
+	return NewScrubber(&config.Scrubber{
+		Interval: time.Hour,
+		// Renew the lease and save the checkpoint after each file.
+		CheckpointInterval: time.Nanosecond,
+		LeaseTTL:           time.Hour,
+		Quarantine:         quarantine,
+	})
+}
+
+// writeLease replaces the lease with the one held by owner until expired.
+func writeLease(t *testing.T, fs fileio.FileIO, owner string, expired time.Time) {
+	t.Helper()
+	ctx := testContext()
+	if err := fs.MkdirAll(ctx, resourceRootDir+"/.scrubber", 0777); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := fs.Remove(ctx, scrubberLeasePath); err != nil && !os.IsNotExist(err) {
+		t.Fatalf("remove lease: %v", err)
+	}
+	if err := writeJSONFile(ctx, fs, scrubberLeasePath, &scrubLease{Owner: owner, Expired: expired.Unix()}); err != nil {
+		t.Fatalf("write lease: %v", err)
+	}
+}
+
+func TestScrubberLease(t *testing.T) {
+	fs := setupStorage(t, nil)
+	ctx := testContext()
+	a, b := newTestScrubber(false), newTestScrubber(false)
+
+	steps := []struct {
+		name string
+		run  func() error
+		want error
+	}{
+		{name: "a acquires", run: func() error { return acquired(a.acquireLease(ctx, fs)) }},
+		{name: "a acquires again", run: func() error { return acquired(a.acquireLease(ctx, fs)) }},
+		{name: "b is rejected", run: func() error { return acquired(b.acquireLease(ctx, fs)) }, want: errScrubLeaseLost},
+		{name: "a renews", run: func() error { return a.renewLease(ctx, fs) }},
+		{name: "b can not renew", run: func() error { return b.renewLease(ctx, fs) }, want: errScrubLeaseLost},
+		{
+			name: "b takes over the expired lease",
+			run: func() error {
+				writeLease(t, fs, a.owner, time.Now().Add(-time.Second))
+				return acquired(b.acquireLease(ctx, fs))
+			},
+		},
+		{name: "a lost the lease", run: func() error { return a.renewLease(ctx, fs) }, want: errScrubLeaseLost},
+		{name: "a does not release the lease of b", run: func() error { return a.releaseLease(ctx, fs) }},
+		{name: "a is rejected", run: func() error { return acquired(a.acquireLease(ctx, fs)) }, want: errScrubLeaseLost},
+		{name: "b releases", run: func() error { return b.releaseLease(ctx, fs) }},
+		{name: "a acquires the released lease", run: func() error { return acquired(a.acquireLease(ctx, fs)) }},
+	}
+	for _, step := range steps {
+		if err := step.run(); err != step.want {
+			t.Fatalf("%s: error = %v, want %v", step.name, err, step.want)
+		}
+	}
+}
+
+// acquired converts the result of acquireLease to error, errScrubLeaseLost if not held.
+func acquired(held bool, err error) error {
+	if err == nil && !held {
+		return errScrubLeaseLost
+	}
+	return err
+}
+
+// takeoverFS records the opened files, and writes the lease of owner when the file name is
+// opened, as if the lease is taken over by another instance while verifying it.
+type takeoverFS struct {
+	fileio.FileIO
+	t      *testing.T
+	name   string
+	owner  string
+	opened map[string]bool
+}
+
+func (fs *takeoverFS) OpenForRead(ctx context.Context, name string) (io.ReadCloser, error) {
+	if fs.opened != nil {
+		fs.opened[name] = true
+	}
+	if name == fs.name {
+		writeLease(fs.t, fs.FileIO, fs.owner, time.Now().Add(time.Hour))
+	}
+	return fs.FileIO.OpenForRead(ctx, name)
+}
+
+func TestScrubberLeaseTakeover(t *testing.T) {
+	x := new(StoreIo)
+	mem := setupStorage(t, nil)
+	ctx := testContext()
+	versions := []string{"0000000000000001", "0000000000000002", "0000000000000003"}
+	for _, version := range versions {
+		if _, err := writeFileData(x, version, "data of "+version, nil); err != nil {
+			t.Fatalf("write %s: %v", version, err)
+		}
+	}
+	a, b := newTestScrubber(false), newTestScrubber(false)
+
+	// The lease is taken over by b when a is verifying the second file.
+	fs := &takeoverFS{FileIO: mem, t: t, name: x.generateResourceFilePath(testSpaceId, testFileId, versions[1]), owner: b.owner}
+	result, err := a.runOnce(ctx, config.DefaultStorageName, fs)
+	if err != nil {
+		t.Fatalf("run a: %v", err)
+	}
+	if result.Scanned != 2 {
+		t.Fatalf("a scanned %d files, want 2", result.Scanned)
+	}
+	cp, err := a.loadCheckpoint(ctx, mem)
+	if err != nil || cp == nil || !strings.HasSuffix(cp.Position, "/"+versions[0]) {
+		t.Fatalf("checkpoint = %+v, %v", cp, err)
+	}
+
+	// The a is rejected until b released the lease.
+	if result, err = a.runOnce(ctx, config.DefaultStorageName, mem); err != nil || result.Scanned != 0 {
+		t.Fatalf("run a again: %+v, %v", result, err)
+	}
+
+	// The b resumes from the checkpoint saved by a, the result of pass includes the file verified by a.
+	fs = &takeoverFS{FileIO: mem, t: t, opened: make(map[string]bool)}
+	if result, err = b.runOnce(ctx, config.DefaultStorageName, fs); err != nil {
+		t.Fatalf("run b: %v", err)
+	}
+	if result.Scanned != 3 || result.Mismatched != 0 {
+		t.Fatalf("b result = %+v", result)
+	}
+	for i, version := range versions {
+		if opened := fs.opened[x.generateResourceFilePath(testSpaceId, testFileId, version)]; opened != (i > 0) {
+			t.Fatalf("b opened %s = %v", version, opened)
+		}
+	}
+	// The pass is complete, the checkpoint and lease are removed.
+	for _, name := range []string{scrubberCheckpointPath, scrubberLeasePath} {
+		if ok, err := mem.IsExists(ctx, name); err != nil || ok {
+			t.Fatalf("%s exists = %v, %v", name, ok, err)
+		}
+	}
+}
+
+func TestScrubberRunOnce(t *testing.T) {
+	for _, quarantine := range []bool{false, true} {
+		t.Run(map[bool]string{false: "report", true: "quarantine"}[quarantine], func(t *testing.T) {
+			x := new(StoreIo)
+			fs := setupStorage(t, nil)
+			ctx := testContext()
+			for _, version := range []string{"0000000000000001", "0000000000000002"} {
+				if _, err := writeFileData(x, version, "hello", nil); err != nil {
+					t.Fatalf("write %s: %v", version, err)
+				}
+			}
+			// Corrupt the second file silently.
+			corrupted := x.generateResourceFilePath(testSpaceId, testFileId, "0000000000000002")
+			if err := fs.Remove(ctx, corrupted); err != nil {
+				t.Fatalf("remove: %v", err)
+			}
+			if _, err := fs.CreateAndWrite(ctx, corrupted, io.NopCloser(strings.NewReader("hellO"))); err != nil {
+				t.Fatalf("corrupt: %v", err)
+			}
+
+			result, err := newTestScrubber(quarantine).RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			want := ScrubResult{Scanned: 2, Bytes: 10, Mismatched: 1}
+			if quarantine {
+				want.Quarantined = 1
+			}
+			if *result != want {
+				t.Fatalf("result = %+v, want %+v", *result, want)
+			}
+			if ok, _ := fs.IsExists(ctx, corrupted); ok == quarantine {
+				t.Fatalf("corrupted file exists = %v", ok)
+			}
+		})
+	}
+}
@@ -1,21 +1,65 @@
 package controller
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"io"
+	"os"
+	"path"
+	"regexp"
+	"sync"
+	"time"
 
 	"github.com/DataWorkbench/common/lib/storeio"
 	"github.com/DataWorkbench/common/qerror"
 	"github.com/DataWorkbench/glog"
-	"github.com/DataWorkbench/gproto/xgo/service/pbsvcstoreio"
 	"github.com/DataWorkbench/gproto/xgo/types/pbmodel"
 	"github.com/DataWorkbench/gproto/xgo/types/pbrequest"
 	"github.com/DataWorkbench/gproto/xgo/types/pbresponse"
+	"github.com/DataWorkbench/resourcemanager/config"
 	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+	"google.golang.org/grpc/metadata"
 )
 
+// hadoopUserMetadataKey is the gRPC metadata key that caller specified the user to access HDFS.
+const hadoopUserMetadataKey = "x-hadoop-user"
+
 type StoreIo struct {
-	pbsvcstoreio.UnimplementedStoreIOServer
+	pbstoreio.UnimplementedStoreIOServer
+}
+
+// resourceRootDir is the parent directory of all workspaces.
+var resourceRootDir = path.Dir(storeio.GenerateWorkspaceDir("-"))
+
+// SweepStagingFiles removes the stale staging files left by the writes that interrupted by crash.
+func SweepStagingFiles(ctx context.Context, expire time.Duration) {
+	lg := glog.FromContext(ctx)
+	for _, name := range options.Storages.Names() {
+		fs, _ := options.Storages.Get(name)
+		removed, err := fileio.SweepStagingFiles(ctx, fs, resourceRootDir, expire)
+		if err != nil {
+			lg.Error().Msg("sweep stale staging files failed").String("storage", name).Int("removed", removed).
+				Error("error", err).Fire()
+			continue
+		}
+		lg.Info().Msg("sweep stale staging files done").String("storage", name).Int("removed", removed).Fire()
+	}
+}
+
+// storage returns the FileIO that the workspace routed to.
+func (x *StoreIo) storage(spaceId string) fileio.FileIO {
+	_, fs := options.Storages.Route(spaceId)
+	return fs
+}
+
+// storageForRead returns the FileIO with read cache that the workspace routed to.
+// It is only used to read resource files, their paths are immutable.
+func (x *StoreIo) storageForRead(spaceId string) fileio.FileIO {
+	_, fs := options.Storages.RouteForRead(spaceId)
+	return fs
 }
 
 func (x *StoreIo) generateWorkspaceDir(spaceId string) string {
@@ -30,15 +74,86 @@ func (x *StoreIo) generateResourceFilePath(spaceId, fileId, version string) stri
 	return storeio.GenerateResourceFilePath(spaceId, fileId, version)
 }
 
-func (x *StoreIo) ensureRootDirExists(ctx context.Context, spaceId, fileId string) (err error) {
+// proxyUserPatterns caches the compiled hadoop_proxy_user_pattern, it may be changed by reload.
+var proxyUserPatterns sync.Map
+
+// matchProxyUser reports whether the user matches the pattern entirely.
+func matchProxyUser(pattern string, user string) bool {
+	v, ok := proxyUserPatterns.Load(pattern)
+	if !ok {
+		// The pattern is checked at config load.
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return false
+		}
+		v, _ = proxyUserPatterns.LoadOrStore(pattern, re)
+	}
+	return v.(*regexp.Regexp).MatchString(user)
+}
+
+// withProxyUser returns a context carry the user to impersonate with the proxy user strategy.
+// The ctx is returned directly if proxy user disabled or no user specified. The user specified
+// by caller is rejected if it not matches the hadoop_proxy_user_pattern.
+func (x *StoreIo) withProxyUser(ctx context.Context, spaceId string) (context.Context, error) {
+	if options.Config == nil || options.Config.Storage == nil {
+		return ctx, nil
+	}
+	var user string
+	switch options.Config.Storage.HadoopProxyUser {
+	case config.HadoopProxyUserWorkspace:
+		user = spaceId
+	case config.HadoopProxyUserCaller:
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(hadoopUserMetadataKey); len(values) > 0 {
+				user = values[0]
+			}
+		}
+		if user != "" && !matchProxyUser(options.Config.Storage.HadoopProxyUserPattern, user) {
+			glog.FromContext(ctx).Warn().Msg("reject the hadoop user specified by caller").String("user", user).Fire()
+			return ctx, qerror.PermissionDenied
+		}
+	}
+	if user == "" {
+		return ctx, nil
+	}
+	return fileio.ContextWithUser(ctx, options.Config.Storage.HadoopProxyUserPrefix+user), nil
+}
+
+// readJSONFile reads the file and decodes its content as json into v.
+func readJSONFile(ctx context.Context, fs fileio.FileIO, name string, v interface{}) error {
+	reader, err := fs.OpenForRead(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSONFile creates the file with v encoded as json. The file must not exist.
+func writeJSONFile(ctx context.Context, fs fileio.FileIO, name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fs.CreateAndWrite(ctx, name, io.NopCloser(bytes.NewReader(data)))
+	return err
+}
+
+func (x *StoreIo) ensureRootDirExists(ctx context.Context, fs fileio.FileIO, spaceId, fileId string) (err error) {
 	rootDir := x.generateResourceFileDir(spaceId, fileId)
-	if err = options.FiloIO.MkdirAll(ctx, rootDir, 0777); err != nil {
+	if err = fs.MkdirAll(ctx, rootDir, 0777); err != nil {
 		return
 	}
 	return
 }
 
-func (x *StoreIo) receiveAndWrite(req pbsvcstoreio.StoreIO_WriteFileDataServer, writer io.WriteCloser, fileSize int64) (err error) {
+func (x *StoreIo) receiveAndWrite(req pbstoreio.StoreIO_WriteFileDataServer, writer io.WriteCloser, fileSize int64) (err error) {
 	var (
 		recv        *pbrequest.WriteFileData
 		receiveSize int64
@@ -73,14 +188,16 @@ func (x *StoreIo) receiveAndWrite(req pbsvcstoreio.StoreIO_WriteFileDataServer,
 	}
 }
 
-func (x *StoreIo) WriteFileData(req pbsvcstoreio.StoreIO_WriteFileDataServer) (err error) {
+func (x *StoreIo) WriteFileData(req pbstoreio.StoreIO_WriteFileDataServer) (err error) {
 	var (
-		recv *pbrequest.WriteFileData
-		eTag string
+		recv     *pbrequest.WriteFileData
+		expected *fileio.Digest
+		digest   *fileio.Digest
 	)
 
 	ctx := req.Context()
 	lg := glog.FromContext(ctx)
+	defer trackStream("WriteFileData")()
 
 	// Receive first stream data. Only metadata. No data.
 	if recv, err = req.Recv(); err != nil {
@@ -96,19 +213,35 @@ func (x *StoreIo) WriteFileData(req pbsvcstoreio.StoreIO_WriteFileDataServer) (e
 		return qerror.Internal
 	}
 
-	if err = x.ensureRootDirExists(ctx, recv.SpaceId, recv.FileId); err != nil {
+	// The client can specify the expected checksum in metadata to verify the data.
+	if expected, err = expectedDigestFromMetadata(ctx); err != nil {
+		return
+	}
+
+	ctx, end, err := beginTransfer(ctx, Transfer{Method: "WriteFileData", SpaceId: recv.SpaceId, FileId: recv.FileId, Version: recv.Version})
+	if err != nil {
+		return
+	}
+	defer end()
+
+	if ctx, err = x.withProxyUser(ctx, recv.SpaceId); err != nil {
+		return
+	}
+	fs := x.storage(recv.SpaceId)
+	if err = x.ensureRootDirExists(ctx, fs, recv.SpaceId, recv.FileId); err != nil {
 		return err
 	}
 
 	fileSize := recv.Size
-	filePath := x.generateResourceFilePath(recv.SpaceId, recv.FileId, recv.Version)
 
 	reader, writer := io.Pipe()
 
+	// The file is published by storage only after the receiveAndWrite succeeds,
+	// so no need to clean it up on failure.
 	defer func() {
 		if err != nil {
 			lg.Error().Msg("write data to storage failed").Error("error", err).Fire()
-			_ = options.FiloIO.Remove(ctx, filePath)
+			err = drainError(ctx, err)
 		}
 		_ = writer.Close()
 		_ = reader.Close()
@@ -120,11 +253,22 @@ func (x *StoreIo) WriteFileData(req pbsvcstoreio.StoreIO_WriteFileDataServer) (e
 	go func() {
 		lg.Debug().Msg("start to write data to storage").Int64("size", fileSize).Fire()
 		writeError = x.receiveAndWrite(req, writer, fileSize)
-		_ = writer.Close()
+		// Close with error to make the storage abort the write, avoid to publish an incomplete file.
+		_ = writer.CloseWithError(writeError)
 		close(done)
 	}()
 
-	if eTag, err = options.FiloIO.CreateAndWrite(ctx, filePath, reader); err != nil {
+	go func() {
+		select {
+		case <-ctx.Done():
+			// The receiving may be blocked, close the writer with error to make the storage
+			// abort the write and clean up the partial file.
+			_ = writer.CloseWithError(ctx.Err())
+		case <-done:
+		}
+	}()
+
+	if digest, err = x.createFileData(ctx, fs, recv.SpaceId, recv.FileId, recv.Version, reader, expected); err != nil {
 		return
 	}
 
@@ -134,27 +278,77 @@ func (x *StoreIo) WriteFileData(req pbsvcstoreio.StoreIO_WriteFileDataServer) (e
 		return
 	}
 
-	lg.Debug().Msg("write data to storage end").String("eTag", eTag).Fire()
-	_ = req.SendAndClose(&pbresponse.WriteFileData{Etag: eTag})
+	lg.Debug().Msg("write data to storage end").String("eTag", digest.MD5).String("sha256", digest.SHA256).Fire()
+	transferBytes.WithLabelValues("upload").Add(float64(fileSize))
+	observeUpload(fileSize)
+	_ = req.SetHeader(digestMetadata(digest))
+	_ = req.SendAndClose(&pbresponse.WriteFileData{Etag: digest.MD5})
 	return
 }
-func (x *StoreIo) ReadFileData(req *pbrequest.ReadFileData, reply pbsvcstoreio.StoreIO_ReadFileDataServer) (err error) {
+
+// ReadFileData sends the data of resource file. Only the data in the range is sent if offset or length specified.
+func (x *StoreIo) ReadFileData(req *pbstoreio.ReadFileDataRequest, reply pbstoreio.StoreIO_ReadFileDataServer) (err error) {
 	var (
 		reader io.ReadCloser
 	)
 
-	ctx := reply.Context()
+	defer trackStream("ReadFileData")()
+	ctx, end, err := beginTransfer(reply.Context(), Transfer{Method: "ReadFileData", SpaceId: req.SpaceId, FileId: req.FileId, Version: req.Version})
+	if err != nil {
+		return
+	}
+	defer end()
+	defer func() {
+		err = drainError(ctx, err)
+	}()
+
+	if ctx, err = x.withProxyUser(ctx, req.SpaceId); err != nil {
+		return
+	}
 	filePath := x.generateResourceFilePath(req.SpaceId, req.FileId, req.Version)
-	if reader, err = options.FiloIO.OpenForRead(ctx, filePath); err != nil {
+	if req.Offset != 0 || req.Length != 0 {
+		if reader, err = x.storageForRead(req.SpaceId).OpenRange(ctx, filePath, req.Offset, req.Length); err != nil {
+			if err == fileio.ErrInvalidRange {
+				return qerror.InvalidParams.Format("offset")
+			}
+			return err
+		}
+		defer func() {
+			_ = reader.Close()
+		}()
+		return x.sendFileData(ctx, reader, reply)
+	}
+
+	if reader, err = x.storageForRead(req.SpaceId).OpenForRead(ctx, filePath); err != nil {
 		return err
 	}
 	defer func() {
 		_ = reader.Close()
 	}()
 
+	// Returns the digest stored at upload in header, so the client can verify the data.
+	// Not for the range, the digest is of the whole file.
+	digest, err := x.readDigest(ctx, x.storage(req.SpaceId), req.SpaceId, req.FileId, req.Version)
+	if err != nil {
+		glog.FromContext(ctx).Warn().Msg("read digest of file data failed").Error("error", err).Fire()
+	}
+	if digest != nil {
+		if err = reply.SendHeader(digestMetadata(digest)); err != nil {
+			return err
+		}
+	}
+
+	return x.sendFileData(ctx, reader, reply)
+}
+
+func (x *StoreIo) sendFileData(ctx context.Context, reader io.Reader, reply pbstoreio.StoreIO_ReadFileDataServer) (err error) {
 	n := 0
 	buf := make([]byte, 4096)
 	for {
+		// Stop sending if the transfer cancelled.
+		if err = ctx.Err(); err != nil {
+			return err
+		}
 		n, err = reader.Read(buf)
 		if err == io.EOF {
 			err = nil
@@ -166,38 +360,134 @@ func (x *StoreIo) ReadFileData(req *pbrequest.ReadFileData, reply pbsvcstoreio.S
 			break
 		}
 		data := buf[:n]
-		err = reply.Send(&pbresponse.ReadFileData{Data: data})
+		err = sendContext(ctx, func() error {
+			return reply.Send(&pbresponse.ReadFileData{Data: data})
+		})
 		if err != nil {
 			return err
 		}
+		transferBytes.WithLabelValues("download").Add(float64(n))
 	}
 	return
 }
+
+// sendContext calls send and returns ctx.Err() without waiting for it if ctx is done first,
+// such as the transfer cancelled by drain while Send is blocked by a client that stops reading.
+// The stream must not be used after that, the blocked Send returns when the handler returns.
+func sendContext(ctx context.Context, send func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- send()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (x *StoreIo) DeleteFileData(ctx context.Context, req *pbrequest.DeleteFileData) (*pbmodel.EmptyStruct, error) {
-	filePath := x.generateResourceFilePath(req.SpaceId, req.FileId, req.Version)
-	err := options.FiloIO.Remove(ctx, filePath)
+	ctx, err := x.withProxyUser(ctx, req.SpaceId)
 	if err != nil {
 		return nil, err
 	}
-	return options.EmptyRPCReply, nil
-}
-func (x *StoreIo) DeleteFileDataByFileIds(ctx context.Context, req *pbrequest.DeleteFileDataByFileIds) (*pbmodel.EmptyStruct, error) {
-	for _, fileId := range req.FileIds {
-		filePath := x.generateResourceFileDir(req.SpaceId, fileId)
-		err := options.FiloIO.RemoveAll(ctx, filePath)
-		if err != nil {
+	fs := x.storage(req.SpaceId)
+	if trashEnabled() {
+		if err := x.moveToTrash(ctx, fs, req.SpaceId, req.FileId, req.Version); err != nil {
 			return nil, err
 		}
+		return options.EmptyRPCReply, nil
+	}
+	filePath := x.generateResourceFilePath(req.SpaceId, req.FileId, req.Version)
+	if err = fs.Remove(ctx, filePath); err != nil {
+		return nil, err
+	}
+	digestPath := x.generateDigestPath(req.SpaceId, req.FileId, req.Version)
+	if err = fs.Remove(ctx, digestPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
 	}
 	return options.EmptyRPCReply, nil
 }
-func (x *StoreIo) DeleteFileDataBySpaceIds(ctx context.Context, req *pbrequest.DeleteFileDataBySpaceIds) (*pbmodel.EmptyStruct, error) {
-	for _, spaceId := range req.SpaceIds {
-		rootDir := x.generateWorkspaceDir(spaceId)
-		err := options.FiloIO.RemoveAll(ctx, rootDir)
-		if err != nil {
-			return nil, err
+
+// DeleteFileDataByFileIds deletes the resource files concurrently and returns the result of each one.
+// The failures are reported in the results, the ones not attempted after a failure are reported as
+// skipped if ContinueOnError is false.
+func (x *StoreIo) DeleteFileDataByFileIds(ctx context.Context, req *pbstoreio.DeleteFileDataByFileIdsRequest) (*pbstoreio.DeleteFileDataReply, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	results, err := x.batchDeleteFileData(ctx, req.SpaceId, req.FileIds, req.ContinueOnError)
+	if results == nil {
+		return nil, err
+	}
+	return &pbstoreio.DeleteFileDataReply{Results: results}, nil
+}
+
+// DeleteFileDataBySpaceIds deletes all resource files of workspaces and returns the result of each workspace.
+// The failures are reported in the results same as DeleteFileDataByFileIds.
+func (x *StoreIo) DeleteFileDataBySpaceIds(ctx context.Context, req *pbstoreio.DeleteFileDataBySpaceIdsRequest) (*pbstoreio.DeleteFileDataReply, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	results, _ := x.batchDeleteWorkspaces(ctx, req.SpaceIds, req.ContinueOnError)
+	return &pbstoreio.DeleteFileDataReply{Results: results}, nil
+}
+
+// StatFileData returns the metadata of resource file without download it.
+func (x *StoreIo) StatFileData(ctx context.Context, req *pbstoreio.StatFileDataRequest) (*pbstoreio.StatFileDataReply, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, err := x.withProxyUser(ctx, req.SpaceId)
+	if err != nil {
+		return nil, err
+	}
+	filePath := x.generateResourceFilePath(req.SpaceId, req.FileId, req.Version)
+	info, err := x.storage(req.SpaceId).Stat(ctx, filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, qerror.ResourceNotExists.Format(req.FileId)
 		}
+		return nil, err
 	}
-	return options.EmptyRPCReply, nil
+	reply := &pbstoreio.StatFileDataReply{
+		Size:    info.Size,
+		Updated: info.ModTime.Unix(),
+		Etag:    info.ETag,
+		Extra:   info.Extra,
+	}
+	return reply, nil
+}
+
+// ListFileVersions returns all versions of the resource file that stored in storage.
+func (x *StoreIo) ListFileVersions(ctx context.Context, req *pbstoreio.ListFileVersionsRequest) (*pbstoreio.ListFileVersionsReply, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, err := x.withProxyUser(ctx, req.SpaceId)
+	if err != nil {
+		return nil, err
+	}
+	fileDir := x.generateResourceFileDir(req.SpaceId, req.FileId)
+	infos, err := x.storage(req.SpaceId).List(ctx, fileDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, qerror.ResourceNotExists.Format(req.FileId)
+		}
+		return nil, err
+	}
+	reply := &pbstoreio.ListFileVersionsReply{Infos: make([]*pbstoreio.FileVersion, 0, len(infos))}
+	for _, info := range infos {
+		if info.IsDir || fileio.IsStagingFile(info.Name) {
+			continue
+		}
+		reply.Infos = append(reply.Infos, &pbstoreio.FileVersion{
+			Version: path.Base(info.Name),
+			Size:    info.Size,
+			Updated: info.ModTime.Unix(),
+			Etag:    info.ETag,
+		})
+	}
+	return reply, nil
 }
@@ -0,0 +1,529 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/gproto/xgo/types/pbrequest"
+	"github.com/DataWorkbench/gproto/xgo/types/pbresponse"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	testSpaceId = "wks-0000000000000001"
+	testFileId  = "res-0000000000000001"
+	testVersion = "0000000000000001"
+)
+
+// testContext returns a context with the logger that only exports the fatal records.
+func testContext() context.Context {
+	return glog.WithContext(context.Background(), glog.NewDefault().WithLevel(glog.FatalLevel))
+}
+
+// isError reports whether err is the qerror target, the formatted qerror is compared by code.
+func isError(err error, target *qerror.Error) bool {
+	var qe *qerror.Error
+	return errors.As(err, &qe) && qe.Code() == target.Code()
+}
+
+// setupStorage replaces the options with a config of defaults and an in-memory storage as
+// the default, the options are restored after the test. The config can be changed by fn.
+func setupStorage(t *testing.T, fn func(cfg *config.Config)) *fileio.Memory {
+	t.Helper()
+	cfg := &config.Config{
+		Storage: &config.Storage{
+			Background:         config.StorageBackgroundMemory,
+			UploadSessionTTL:   time.Hour,
+			DeleteConcurrency:  2,
+			MigrateConcurrency: 2,
+		},
+		Trash: &config.Trash{Retention: time.Hour, PurgeInterval: time.Hour},
+	}
+	if fn != nil {
+		fn(cfg)
+	}
+
+	oldConfig, oldStorages := options.Config, options.Storages
+	options.Config, options.Storages = cfg, fileio.NewRegistry()
+	t.Cleanup(func() {
+		options.Config, options.Storages = oldConfig, oldStorages
+	})
+	return addStorage(t, config.DefaultStorageName)
+}
+
+// addStorage registers an in-memory storage with name to the options.
+func addStorage(t *testing.T, name string) *fileio.Memory {
+	t.Helper()
+	fs, err := fileio.NewMemory(testContext(), 0)
+	if err != nil {
+		t.Fatalf("new memory: %v", err)
+	}
+	if err = options.Storages.Register(name, fs); err != nil {
+		t.Fatalf("register storage: %v", err)
+	}
+	return fs
+}
+
+// writeStream is the server stream of WriteFileData that receives the messages in order.
+type writeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	recvs  []*pbrequest.WriteFileData
+	reply  *pbresponse.WriteFileData
+	header metadata.MD
+}
+
+func (s *writeStream) Context() context.Context { return s.ctx }
+
+func (s *writeStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *writeStream) Recv() (*pbrequest.WriteFileData, error) {
+	if len(s.recvs) == 0 {
+		return nil, io.EOF
+	}
+	recv := s.recvs[0]
+	s.recvs = s.recvs[1:]
+	return recv, nil
+}
+
+func (s *writeStream) SendAndClose(reply *pbresponse.WriteFileData) error {
+	s.reply = reply
+	return nil
+}
+
+// readStream is the server stream of ReadFileData that collects the sent data.
+type readStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	data   bytes.Buffer
+	header metadata.MD
+}
+
+func (s *readStream) Context() context.Context { return s.ctx }
+
+func (s *readStream) SendHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *readStream) Send(reply *pbresponse.ReadFileData) error {
+	s.data.Write(reply.Data)
+	return nil
+}
+
+// writeFileData uploads the data as the version of test file, with the metadata md if not nil.
+func writeFileData(x *StoreIo, version string, data string, md metadata.MD) (*writeStream, error) {
+	ctx := testContext()
+	if md != nil {
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	stream := &writeStream{
+		ctx: ctx,
+		recvs: []*pbrequest.WriteFileData{
+			{SpaceId: testSpaceId, FileId: testFileId, Version: version, Size: int64(len(data))},
+			{Data: []byte(data)},
+		},
+	}
+	return stream, x.WriteFileData(stream)
+}
+
+// readFileData downloads the data of test file in range.
+func readFileData(x *StoreIo, version string, offset, length int64) (*readStream, error) {
+	stream := &readStream{ctx: testContext()}
+	req := &pbstoreio.ReadFileDataRequest{
+		SpaceId: testSpaceId, FileId: testFileId, Version: version, Offset: offset, Length: length,
+	}
+	return stream, x.ReadFileData(req, stream)
+}
+
+func TestStoreIoWriteFileData(t *testing.T) {
+	tests := []struct {
+		name   string
+		recvs  []*pbrequest.WriteFileData
+		md     metadata.MD
+		exists bool
+	}{
+		{
+			name: "data in first message",
+			recvs: []*pbrequest.WriteFileData{
+				{SpaceId: testSpaceId, FileId: testFileId, Version: testVersion, Size: 5, Data: []byte("hello")},
+			},
+		},
+		{
+			name: "data lost",
+			recvs: []*pbrequest.WriteFileData{
+				{SpaceId: testSpaceId, FileId: testFileId, Version: testVersion, Size: 10},
+				{Data: []byte("hello")},
+			},
+		},
+		{
+			name: "checksum mismatch",
+			recvs: []*pbrequest.WriteFileData{
+				{SpaceId: testSpaceId, FileId: testFileId, Version: testVersion, Size: 5},
+				{Data: []byte("hello")},
+			},
+			md: metadata.Pairs(checksumMD5MetadataKey, "00000000000000000000000000000000"),
+		},
+		{
+			name: "invalid space id",
+			recvs: []*pbrequest.WriteFileData{
+				{SpaceId: "wks-1", FileId: testFileId, Version: testVersion, Size: 5},
+				{Data: []byte("hello")},
+			},
+		},
+		{
+			name: "version exists",
+			recvs: []*pbrequest.WriteFileData{
+				{SpaceId: testSpaceId, FileId: testFileId, Version: testVersion, Size: 5},
+				{Data: []byte("world")},
+			},
+			exists: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := new(StoreIo)
+			fs := setupStorage(t, nil)
+			if tt.exists {
+				if _, err := writeFileData(x, testVersion, "hello", nil); err != nil {
+					t.Fatalf("write: %v", err)
+				}
+			}
+			ctx := testContext()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			if err := x.WriteFileData(&writeStream{ctx: ctx, recvs: tt.recvs}); err == nil {
+				t.Fatal("expected error")
+			}
+
+			filePath := x.generateResourceFilePath(testSpaceId, testFileId, testVersion)
+			exists, err := fs.IsExists(ctx, filePath)
+			if err != nil {
+				t.Fatalf("is exists: %v", err)
+			}
+			if exists != tt.exists {
+				t.Fatalf("file exists = %v, want %v", exists, tt.exists)
+			}
+		})
+	}
+}
+
+func TestStoreIoReadFileData(t *testing.T) {
+	x := new(StoreIo)
+	setupStorage(t, nil)
+	const data = "0123456789"
+	written, err := writeFileData(x, testVersion, data, nil)
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if written.reply.Etag != "781e5e245d69b566979b86e28d23f2c7" {
+		t.Fatalf("eTag = %s", written.reply.Etag)
+	}
+	if got := written.header.Get(checksumMD5MetadataKey); len(got) != 1 || got[0] != written.reply.Etag {
+		t.Fatalf("md5 header = %v", got)
+	}
+
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   string
+		digest bool
+		err    *qerror.Error
+	}{
+		{name: "all", want: data, digest: true},
+		{name: "range", offset: 3, length: 4, want: "3456"},
+		{name: "to end", offset: 8, want: "89"},
+		{name: "beyond end", offset: 11, length: 1, err: qerror.InvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := readFileData(x, testVersion, tt.offset, tt.length)
+			if tt.err != nil {
+				if !isError(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if stream.data.String() != tt.want {
+				t.Fatalf("data = %q, want %q", stream.data.String(), tt.want)
+			}
+			// The digest is of the whole file, so only sent for the full read.
+			if got := len(stream.header.Get(checksumSHA256MetadataKey)) != 0; got != tt.digest {
+				t.Fatalf("digest sent = %v, want %v", got, tt.digest)
+			}
+		})
+	}
+
+	if _, err = readFileData(x, "0000000000000002", 0, 0); !os.IsNotExist(err) {
+		t.Fatalf("read not exists: %v", err)
+	}
+}
+
+func TestStoreIoStatAndListFileVersions(t *testing.T) {
+	x := new(StoreIo)
+	setupStorage(t, nil)
+	ctx := testContext()
+
+	_, err := x.ListFileVersions(ctx, &pbstoreio.ListFileVersionsRequest{SpaceId: testSpaceId, FileId: testFileId})
+	if !isError(err, qerror.ResourceNotExists) {
+		t.Fatalf("list not exists: %v", err)
+	}
+
+	versions := []struct {
+		version string
+		data    string
+	}{
+		{version: "0000000000000002", data: "world!"},
+		{version: testVersion, data: "hello"},
+	}
+	for _, v := range versions {
+		if _, err = writeFileData(x, v.version, v.data, nil); err != nil {
+			t.Fatalf("write %s: %v", v.version, err)
+		}
+	}
+
+	for _, v := range versions {
+		reply, err := x.StatFileData(ctx, &pbstoreio.StatFileDataRequest{SpaceId: testSpaceId, FileId: testFileId, Version: v.version})
+		if err != nil {
+			t.Fatalf("stat %s: %v", v.version, err)
+		}
+		if reply.Size != int64(len(v.data)) || reply.Etag == "" {
+			t.Fatalf("stat %s = %+v", v.version, reply)
+		}
+	}
+	_, err = x.StatFileData(ctx, &pbstoreio.StatFileDataRequest{SpaceId: testSpaceId, FileId: testFileId, Version: "0000000000000003"})
+	if !isError(err, qerror.ResourceNotExists) {
+		t.Fatalf("stat not exists: %v", err)
+	}
+
+	// The digest sidecar directory is not a version.
+	reply, err := x.ListFileVersions(ctx, &pbstoreio.ListFileVersionsRequest{SpaceId: testSpaceId, FileId: testFileId})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(reply.Infos) != 2 || reply.Infos[0].Version != testVersion || reply.Infos[1].Version != "0000000000000002" ||
+		reply.Infos[0].Size != 5 || reply.Infos[1].Size != 6 {
+		t.Fatalf("list = %v", reply.Infos)
+	}
+}
+
+func TestStoreIoListFileVersionsSkipStaging(t *testing.T) {
+	x := new(StoreIo)
+	fs := setupStorage(t, nil)
+	ctx := testContext()
+	if _, err := writeFileData(x, testVersion, "hello", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// The staging file left by an upload in progress or crashed.
+	staging := x.generateResourceFilePath(testSpaceId, testFileId, "0000000000000002") + ".staging-1"
+	if _, err := fs.CreateAndWrite(ctx, staging, io.NopCloser(bytes.NewReader([]byte("partial")))); err != nil {
+		t.Fatalf("write staging file: %v", err)
+	}
+
+	reply, err := x.ListFileVersions(ctx, &pbstoreio.ListFileVersionsRequest{SpaceId: testSpaceId, FileId: testFileId})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(reply.Infos) != 1 || reply.Infos[0].Version != testVersion {
+		t.Fatalf("list = %v", reply.Infos)
+	}
+	if _, err = x.ListFileVersions(ctx, &pbstoreio.ListFileVersionsRequest{SpaceId: testSpaceId, FileId: "res-1"}); err == nil {
+		t.Fatal("expected error for invalid file id")
+	}
+}
+
+func TestStoreIoStatFileData(t *testing.T) {
+	x := new(StoreIo)
+	setupStorage(t, nil)
+	ctx := testContext()
+	before := time.Now().Unix()
+	written, err := writeFileData(x, testVersion, "hello", nil)
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     *pbstoreio.StatFileDataRequest
+		err     *qerror.Error
+		invalid bool
+	}{
+		{
+			name: "exists",
+			req:  &pbstoreio.StatFileDataRequest{SpaceId: testSpaceId, FileId: testFileId, Version: testVersion},
+		},
+		{
+			name: "not exists",
+			req:  &pbstoreio.StatFileDataRequest{SpaceId: testSpaceId, FileId: testFileId, Version: "0000000000000002"},
+			err:  qerror.ResourceNotExists,
+		},
+		{
+			name:    "invalid space id",
+			req:     &pbstoreio.StatFileDataRequest{SpaceId: "wks-1", FileId: testFileId, Version: testVersion},
+			invalid: true,
+		},
+		{
+			name:    "invalid version",
+			req:     &pbstoreio.StatFileDataRequest{SpaceId: testSpaceId, FileId: testFileId},
+			invalid: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, err := x.StatFileData(ctx, tt.req)
+			switch {
+			case tt.err != nil:
+				if !isError(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+			case tt.invalid:
+				if err == nil {
+					t.Fatal("expected error for invalid request")
+				}
+			case err != nil:
+				t.Fatalf("stat: %v", err)
+			case reply.Size != 5 || reply.Etag != written.reply.Etag || reply.Updated < before:
+				t.Fatalf("stat = %+v", reply)
+			}
+		})
+	}
+}
+
+func TestStoreIoDeleteFileData(t *testing.T) {
+	x := new(StoreIo)
+	fs := setupStorage(t, nil)
+	ctx := testContext()
+	if _, err := writeFileData(x, testVersion, "hello", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	req := &pbrequest.DeleteFileData{SpaceId: testSpaceId, FileId: testFileId, Version: testVersion}
+	if _, err := x.DeleteFileData(ctx, req); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	for _, name := range []string{
+		x.generateResourceFilePath(testSpaceId, testFileId, testVersion),
+		x.generateDigestPath(testSpaceId, testFileId, testVersion),
+	} {
+		if exists, err := fs.IsExists(ctx, name); err != nil || exists {
+			t.Fatalf("%s exists = %v, %v", name, exists, err)
+		}
+	}
+	if _, err := x.DeleteFileData(ctx, req); !os.IsNotExist(err) {
+		t.Fatalf("delete again: %v", err)
+	}
+}
+
+func TestMatchProxyUser(t *testing.T) {
+	tests := []struct {
+		pattern string
+		user    string
+		want    bool
+	}{
+		{pattern: "etl_[a-z]+", user: "etl_daily", want: true},
+		{pattern: "etl_[a-z]+", user: "etl_daily2", want: false},
+		{pattern: "etl_[a-z]+", user: "xetl_daily", want: false},
+		{pattern: "a|b", user: "b", want: true},
+		{pattern: "a|b", user: "ab", want: false},
+		{pattern: "(", user: "(", want: false},
+	}
+	for _, tt := range tests {
+		if got := matchProxyUser(tt.pattern, tt.user); got != tt.want {
+			t.Errorf("matchProxyUser(%q, %q) = %v, want %v", tt.pattern, tt.user, got, tt.want)
+		}
+	}
+}
+
+func TestWithProxyUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		caller   string
+		want     string
+		err      *qerror.Error
+	}{
+		{name: "disabled", caller: "etl_daily"},
+		{name: "workspace", strategy: config.HadoopProxyUserWorkspace, want: "ws_" + testSpaceId},
+		{name: "caller", strategy: config.HadoopProxyUserCaller, caller: "etl_daily", want: "ws_etl_daily"},
+		{name: "caller not specified", strategy: config.HadoopProxyUserCaller},
+		{name: "caller rejected", strategy: config.HadoopProxyUserCaller, caller: "hdfs", err: qerror.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupStorage(t, func(cfg *config.Config) {
+				cfg.Storage.HadoopProxyUser = tt.strategy
+				cfg.Storage.HadoopProxyUserPattern = "etl_[a-z]+"
+				cfg.Storage.HadoopProxyUserPrefix = "ws_"
+			})
+			ctx := testContext()
+			if tt.caller != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(hadoopUserMetadataKey, tt.caller))
+			}
+			ctx, err := new(StoreIo).withProxyUser(ctx, testSpaceId)
+			if tt.err != nil {
+				if !isError(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("with proxy user: %v", err)
+			}
+			if got := fileio.UserFromContext(ctx); got != tt.want {
+				t.Fatalf("user = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendContext(t *testing.T) {
+	errSend := errors.New("send failed")
+	tests := []struct {
+		name   string
+		send   func(release <-chan struct{}) error
+		cancel bool
+		want   error
+	}{
+		{name: "sent", send: func(<-chan struct{}) error { return nil }},
+		{name: "send failed", send: func(<-chan struct{}) error { return errSend }, want: errSend},
+		{
+			// The Send is blocked by a client that stops reading until the handler returns.
+			name:   "blocked and cancelled",
+			send:   func(release <-chan struct{}) error { <-release; return nil },
+			cancel: true,
+			want:   context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		if tt.cancel {
+			time.AfterFunc(10*time.Millisecond, cancel)
+		}
+		if err := sendContext(ctx, func() error { return tt.send(release) }); err != tt.want {
+			t.Fatalf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+		close(release)
+		cancel()
+	}
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrShuttingDown is returned to the transfers that rejected or cancelled by the drain
+// on shutdown. The clients should retry on another server.
+var ErrShuttingDown = status.Error(codes.Unavailable, "resourcemanager: server is shutting down")
+
+// Transfer describes a transfer of file data in flight.
+type Transfer struct {
+	Method  string
+	SpaceId string
+	FileId  string
+	// The version of file, empty for the transfers of upload session.
+	Version string
+	// The upload session id, empty for the other transfers.
+	SessionId string
+	Started   time.Time
+}
+
+type transfer struct {
+	Transfer
+	cancel context.CancelFunc
+}
+
+// transfers tracks the transfers in flight, so they can be drained on shutdown.
+var transfers = struct {
+	sync.Mutex
+	draining bool
+	set      map[*transfer]struct{}
+}{set: make(map[*transfer]struct{})}
+
+// beginTransfer registers the transfer t. It returns the context that is cancelled by CancelTransfers
+// and the func to call when the transfer finished. Returns ErrShuttingDown if draining.
+func beginTransfer(ctx context.Context, t Transfer) (context.Context, func(), error) {
+	transfers.Lock()
+	defer transfers.Unlock()
+	if transfers.draining {
+		return ctx, nil, ErrShuttingDown
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	t.Started = time.Now()
+	entry := &transfer{Transfer: t, cancel: cancel}
+	transfers.set[entry] = struct{}{}
+	end := func() {
+		transfers.Lock()
+		delete(transfers.set, entry)
+		transfers.Unlock()
+		cancel()
+	}
+	return ctx, end, nil
+}
+
+// drainError returns ErrShuttingDown if the transfer failed because it was cancelled by the drain.
+func drainError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	transfers.Lock()
+	defer transfers.Unlock()
+	if transfers.draining {
+		return ErrShuttingDown
+	}
+	return err
+}
+
+// StartDrain rejects the new transfers with ErrShuttingDown, the transfers in flight continue.
+func StartDrain() {
+	transfers.Lock()
+	transfers.draining = true
+	transfers.Unlock()
+}
+
+// InFlightTransfers returns the transfers in flight ordered by start time.
+func InFlightTransfers() []Transfer {
+	transfers.Lock()
+	list := make([]Transfer, 0, len(transfers.set))
+	for t := range transfers.set {
+		list = append(list, t.Transfer)
+	}
+	transfers.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Started.Before(list[j].Started)
+	})
+	return list
+}
+
+// CancelTransfers cancels all transfers in flight, the partial files are cleaned up by
+// their error path. Returns the number of cancelled transfers.
+func CancelTransfers() int {
+	transfers.Lock()
+	defer transfers.Unlock()
+	for t := range transfers.set {
+		t.cancel()
+	}
+	return len(transfers.set)
+}
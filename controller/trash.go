@@ -0,0 +1,334 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/gproto/xgo/types/pbmodel"
+	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+	"google.golang.org/grpc/metadata"
+)
+
+// operatorMetadataKey is the gRPC metadata key that caller specified the operator, it is recorded in trash.
+const operatorMetadataKey = "x-operator"
+
+// The deleted resource is moved to directory `.trash/<entryId>` under workspace dir. The entry
+// dir contains a metadata file, the deleted file or directory named `data`, and the digest of
+// deleted version named `digest`.
+const (
+	trashDirName    = ".trash"
+	trashMetaName   = "meta.json"
+	trashDataName   = "data"
+	trashDigestName = "digest"
+)
+
+// trashEntry is the metadata of entry in trash.
+type trashEntry struct {
+	EntryId      string `json:"entry_id"`
+	SpaceId      string `json:"space_id"`
+	FileId       string `json:"file_id"`
+	Version      string `json:"version"`
+	OriginalPath string `json:"original_path"`
+	DeletedBy    string `json:"deleted_by"`
+	Deleted      int64  `json:"deleted"`
+}
+
+func newTrashEntryId() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "trh-" + hex.EncodeToString(b), nil
+}
+
+func (x *StoreIo) generateTrashDir(spaceId string) string {
+	return x.generateWorkspaceDir(spaceId) + "/" + trashDirName
+}
+
+func (x *StoreIo) generateTrashEntryDir(spaceId, entryId string) string {
+	return x.generateTrashDir(spaceId) + "/" + entryId
+}
+
+// trashEnabled reports whether to move the deleted resources to trash.
+func trashEnabled() bool {
+	return options.Config != nil && options.Config.Trash != nil && options.Config.Trash.Enabled
+}
+
+// operatorFromContext returns the operator specified by caller, or the user to access storage.
+func operatorFromContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(operatorMetadataKey); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return fileio.UserFromContext(ctx)
+}
+
+// moveToTrash moves the resource file version to trash, or the whole resource file dir if version is empty.
+func (x *StoreIo) moveToTrash(ctx context.Context, fs fileio.FileIO, spaceId, fileId, version string) error {
+	lg := glog.FromContext(ctx)
+
+	var originalPath string
+	if version != "" {
+		originalPath = x.generateResourceFilePath(spaceId, fileId, version)
+	} else {
+		originalPath = x.generateResourceFileDir(spaceId, fileId)
+	}
+	// Check first to avoid to create an empty entry.
+	if _, err := fs.Stat(ctx, originalPath); err != nil {
+		return err
+	}
+
+	entryId, err := newTrashEntryId()
+	if err != nil {
+		return err
+	}
+	entry := &trashEntry{
+		EntryId:      entryId,
+		SpaceId:      spaceId,
+		FileId:       fileId,
+		Version:      version,
+		OriginalPath: originalPath,
+		DeletedBy:    operatorFromContext(ctx),
+		Deleted:      time.Now().Unix(),
+	}
+
+	entryDir := x.generateTrashEntryDir(spaceId, entryId)
+	if err = fs.MkdirAll(ctx, entryDir, 0777); err != nil {
+		return err
+	}
+	if err = writeJSONFile(ctx, fs, entryDir+"/"+trashMetaName, entry); err != nil {
+		_ = fs.RemoveAll(ctx, entryDir)
+		return err
+	}
+	if err = fs.Rename(ctx, originalPath, entryDir+"/"+trashDataName); err != nil {
+		_ = fs.RemoveAll(ctx, entryDir)
+		return err
+	}
+	if version != "" {
+		digestPath := x.generateDigestPath(spaceId, fileId, version)
+		if err = fs.Rename(ctx, digestPath, entryDir+"/"+trashDigestName); err != nil && !os.IsNotExist(err) {
+			lg.Warn().Msg("move digest to trash failed").String("entryId", entryId).Error("error", err).Fire()
+		}
+	}
+
+	lg.Info().Msg("resource moved to trash").String("entryId", entryId).String("originalPath", originalPath).
+		String("deletedBy", entry.DeletedBy).Fire()
+	return nil
+}
+
+// listTrashEntries returns all entries in the trash of workspace.
+func (x *StoreIo) listTrashEntries(ctx context.Context, fs fileio.FileIO, spaceId string) ([]*trashEntry, error) {
+	lg := glog.FromContext(ctx)
+
+	infos, err := fs.List(ctx, x.generateTrashDir(spaceId))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entries := make([]*trashEntry, 0, len(infos))
+	for _, info := range infos {
+		if !info.IsDir {
+			continue
+		}
+		entry := new(trashEntry)
+		if err = readJSONFile(ctx, fs, info.Name+"/"+trashMetaName, entry); err != nil {
+			// The metadata is missing if moveToTrash interrupted, use the directory time instead.
+			lg.Warn().Msg("read trash entry metadata failed").String("name", info.Name).Error("error", err).Fire()
+			entry = &trashEntry{EntryId: path.Base(info.Name), SpaceId: spaceId, Deleted: info.ModTime.Unix()}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func trashEntryExpired(entry *trashEntry) time.Time {
+	return time.Unix(entry.Deleted, 0).Add(options.Config.Trash.Retention)
+}
+
+// ListTrash returns the entries in the trash of workspace.
+func (x *StoreIo) ListTrash(ctx context.Context, req *pbstoreio.ListTrashRequest) (*pbstoreio.ListTrashReply, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, err := x.withProxyUser(ctx, req.SpaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := x.listTrashEntries(ctx, x.storage(req.SpaceId), req.SpaceId)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pbstoreio.ListTrashReply{Entries: make([]*pbstoreio.TrashEntry, 0, len(entries))}
+	for _, entry := range entries {
+		if req.FileId != "" && entry.FileId != req.FileId {
+			continue
+		}
+		reply.Entries = append(reply.Entries, &pbstoreio.TrashEntry{
+			EntryId:      entry.EntryId,
+			FileId:       entry.FileId,
+			Version:      entry.Version,
+			OriginalPath: entry.OriginalPath,
+			DeletedBy:    entry.DeletedBy,
+			Deleted:      entry.Deleted,
+			Expired:      trashEntryExpired(entry).Unix(),
+		})
+	}
+	sort.SliceStable(reply.Entries, func(i, j int) bool {
+		return reply.Entries[i].Deleted > reply.Entries[j].Deleted
+	})
+	return reply, nil
+}
+
+// RestoreTrash moves the entry in trash back to its original path.
+func (x *StoreIo) RestoreTrash(ctx context.Context, req *pbstoreio.RestoreTrashRequest) (*pbmodel.EmptyStruct, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, err := x.withProxyUser(ctx, req.SpaceId)
+	if err != nil {
+		return nil, err
+	}
+	lg := glog.FromContext(ctx)
+	fs := x.storage(req.SpaceId)
+
+	entryDir := x.generateTrashEntryDir(req.SpaceId, req.EntryId)
+	entry := new(trashEntry)
+	if err := readJSONFile(ctx, fs, entryDir+"/"+trashMetaName, entry); err != nil {
+		if os.IsNotExist(err) {
+			return nil, qerror.ResourceNotExists.Format(req.EntryId)
+		}
+		return nil, err
+	}
+
+	if err = fs.MkdirAll(ctx, path.Dir(entry.OriginalPath), 0777); err != nil {
+		return nil, err
+	}
+	// Not overwrite, so the resource uploaded after deleted is kept.
+	if err = fileio.RenameNoReplace(ctx, fs, entryDir+"/"+trashDataName, entry.OriginalPath); err != nil {
+		switch {
+		case os.IsExist(err):
+			return nil, qerror.ResourceAlreadyExists.Format(entry.OriginalPath)
+		case os.IsNotExist(err):
+			return nil, qerror.ResourceNotExists.Format(req.EntryId)
+		}
+		return nil, err
+	}
+	if entry.Version != "" {
+		digestPath := x.generateDigestPath(entry.SpaceId, entry.FileId, entry.Version)
+		if err = fs.MkdirAll(ctx, path.Dir(digestPath), 0777); err != nil {
+			return nil, err
+		}
+		if err = fs.Rename(ctx, entryDir+"/"+trashDigestName, digestPath); err != nil && !os.IsNotExist(err) {
+			lg.Warn().Msg("restore digest from trash failed").String("entryId", req.EntryId).Error("error", err).Fire()
+		}
+	}
+	if err = fs.RemoveAll(ctx, entryDir); err != nil {
+		return nil, err
+	}
+
+	lg.Info().Msg("resource restored from trash").String("entryId", req.EntryId).
+		String("originalPath", entry.OriginalPath).Fire()
+	return options.EmptyRPCReply, nil
+}
+
+// PurgeTrash removes the entries in trash permanently.
+func (x *StoreIo) PurgeTrash(ctx context.Context, req *pbstoreio.PurgeTrashRequest) (*pbmodel.EmptyStruct, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, err := x.withProxyUser(ctx, req.SpaceId)
+	if err != nil {
+		return nil, err
+	}
+	lg := glog.FromContext(ctx)
+	fs := x.storage(req.SpaceId)
+
+	if len(req.EntryIds) == 0 {
+		if err := fs.RemoveAll(ctx, x.generateTrashDir(req.SpaceId)); err != nil {
+			return nil, err
+		}
+		lg.Info().Msg("trash purged").String("spaceId", req.SpaceId).Fire()
+		return options.EmptyRPCReply, nil
+	}
+	for _, entryId := range req.EntryIds {
+		if err := fs.RemoveAll(ctx, x.generateTrashEntryDir(req.SpaceId, entryId)); err != nil {
+			return nil, err
+		}
+		lg.Info().Msg("trash entry purged").String("entryId", entryId).Fire()
+	}
+	return options.EmptyRPCReply, nil
+}
+
+// PurgeExpiredTrash removes the entries that exceed the retention in all workspaces of all storage backends.
+func PurgeExpiredTrash(ctx context.Context) {
+	for _, name := range options.Storages.Names() {
+		fs, _ := options.Storages.Get(name)
+		purgeExpiredTrash(ctx, name, fs)
+	}
+}
+
+func purgeExpiredTrash(ctx context.Context, storage string, fs fileio.FileIO) {
+	lg := glog.FromContext(ctx)
+	x := &StoreIo{}
+
+	infos, err := fs.List(ctx, resourceRootDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			lg.Error().Msg("purge expired trash failed").String("storage", storage).Error("error", err).Fire()
+		}
+		return
+	}
+
+	var purged int
+	now := time.Now()
+	for _, info := range infos {
+		spaceId := path.Base(info.Name)
+		if !info.IsDir || strings.HasPrefix(spaceId, ".") {
+			continue
+		}
+		entries, err := x.listTrashEntries(ctx, fs, spaceId)
+		if err != nil {
+			lg.Error().Msg("list trash entries failed").String("spaceId", spaceId).Error("error", err).Fire()
+			continue
+		}
+		for _, entry := range entries {
+			if now.Before(trashEntryExpired(entry)) {
+				continue
+			}
+			if err = fs.RemoveAll(ctx, x.generateTrashEntryDir(spaceId, entry.EntryId)); err != nil {
+				lg.Error().Msg("purge expired trash entry failed").String("entryId", entry.EntryId).Error("error", err).Fire()
+				continue
+			}
+			purged++
+		}
+	}
+	lg.Info().Msg("purge expired trash done").String("storage", storage).Int("purged", purged).Fire()
+}
+
+// RunTrashPurger purges the expired trash entries periodically until ctx done.
+func RunTrashPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		PurgeExpiredTrash(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/gproto/xgo/types/pbrequest"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+	"google.golang.org/grpc/metadata"
+)
+
+// setupTrash sets up the storage with trash enabled and the retention.
+func setupTrash(t *testing.T, retention time.Duration) {
+	t.Helper()
+	setupStorage(t, func(cfg *config.Config) {
+		cfg.Trash.Enabled = true
+		cfg.Trash.Retention = retention
+	})
+}
+
+// deleteToTrash uploads the data as version and deletes it to trash, returns the trash entry.
+func deleteToTrash(t *testing.T, x *StoreIo, version string, data string) *pbstoreio.TrashEntry {
+	t.Helper()
+	if _, err := writeFileData(x, version, data, nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	ctx := metadata.NewIncomingContext(testContext(), metadata.Pairs(operatorMetadataKey, "usr-1"))
+	if _, err := x.DeleteFileData(ctx, &pbrequest.DeleteFileData{SpaceId: testSpaceId, FileId: testFileId, Version: version}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	entries := listTrash(t, x)
+	for _, entry := range entries {
+		if entry.Version == version {
+			return entry
+		}
+	}
+	t.Fatalf("version %s not in trash", version)
+	return nil
+}
+
+func listTrash(t *testing.T, x *StoreIo) []*pbstoreio.TrashEntry {
+	t.Helper()
+	reply, err := x.ListTrash(testContext(), &pbstoreio.ListTrashRequest{SpaceId: testSpaceId, FileId: testFileId})
+	if err != nil {
+		t.Fatalf("list trash: %v", err)
+	}
+	return reply.Entries
+}
+
+func TestTrashDeleteAndRestore(t *testing.T) {
+	x := new(StoreIo)
+	setupTrash(t, time.Hour)
+	ctx := testContext()
+
+	entry := deleteToTrash(t, x, testVersion, "hello")
+	if entry.FileId != testFileId || entry.DeletedBy != "usr-1" ||
+		entry.OriginalPath != x.generateResourceFilePath(testSpaceId, testFileId, testVersion) ||
+		entry.Expired != entry.Deleted+int64(time.Hour/time.Second) {
+		t.Fatalf("entry = %v", entry)
+	}
+	if _, err := readFileData(x, testVersion, 0, 0); err == nil {
+		t.Fatal("the deleted file is readable")
+	}
+
+	if _, err := x.RestoreTrash(ctx, &pbstoreio.RestoreTrashRequest{SpaceId: testSpaceId, EntryId: entry.EntryId}); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	stream, err := readFileData(x, testVersion, 0, 0)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	// The digest is restored with the file.
+	if stream.data.String() != "hello" || len(stream.header.Get(checksumMD5MetadataKey)) == 0 {
+		t.Fatalf("data = %q, header = %v", stream.data.String(), stream.header)
+	}
+	if entries := listTrash(t, x); len(entries) != 0 {
+		t.Fatalf("entries = %v", entries)
+	}
+
+	_, err = x.RestoreTrash(ctx, &pbstoreio.RestoreTrashRequest{SpaceId: testSpaceId, EntryId: entry.EntryId})
+	if !isError(err, qerror.ResourceNotExists) {
+		t.Fatalf("restore again: %v", err)
+	}
+}
+
+func TestTrashRestoreConflict(t *testing.T) {
+	x := new(StoreIo)
+	setupTrash(t, time.Hour)
+
+	entry := deleteToTrash(t, x, testVersion, "hello")
+	// The same version is uploaded again after deleted.
+	if _, err := writeFileData(x, testVersion, "world", nil); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err := x.RestoreTrash(testContext(), &pbstoreio.RestoreTrashRequest{SpaceId: testSpaceId, EntryId: entry.EntryId})
+	if !isError(err, qerror.ResourceAlreadyExists) {
+		t.Fatalf("restore: %v", err)
+	}
+	stream, err := readFileData(x, testVersion, 0, 0)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if stream.data.String() != "world" {
+		t.Fatalf("the uploaded file is overwritten: %q", stream.data.String())
+	}
+	if entries := listTrash(t, x); len(entries) != 1 {
+		t.Fatalf("entries = %v", entries)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	tests := []struct {
+		name   string
+		purge  func(entries []*pbstoreio.TrashEntry) []string
+		remain int
+	}{
+		{name: "all", purge: func([]*pbstoreio.TrashEntry) []string { return nil }, remain: 0},
+		{name: "specified", purge: func(entries []*pbstoreio.TrashEntry) []string { return []string{entries[0].EntryId} }, remain: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := new(StoreIo)
+			setupTrash(t, time.Hour)
+			deleteToTrash(t, x, testVersion, "hello")
+			deleteToTrash(t, x, "0000000000000002", "world")
+
+			req := &pbstoreio.PurgeTrashRequest{SpaceId: testSpaceId, EntryIds: tt.purge(listTrash(t, x))}
+			if _, err := x.PurgeTrash(testContext(), req); err != nil {
+				t.Fatalf("purge: %v", err)
+			}
+			if entries := listTrash(t, x); len(entries) != tt.remain {
+				t.Fatalf("entries = %d, want %d", len(entries), tt.remain)
+			}
+		})
+	}
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention time.Duration
+		remain    int
+	}{
+		{name: "not expired", retention: time.Hour, remain: 1},
+		{name: "expired", retention: -time.Second, remain: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := new(StoreIo)
+			setupTrash(t, tt.retention)
+			deleteToTrash(t, x, testVersion, "hello")
+
+			PurgeExpiredTrash(testContext())
+			if entries := listTrash(t, x); len(entries) != tt.remain {
+				t.Fatalf("entries = %d, want %d", len(entries), tt.remain)
+			}
+		})
+	}
+}
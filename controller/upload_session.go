@@ -0,0 +1,445 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+)
+
+// The upload session is persisted in the directory named `.upload-<sessionId>` under resource file dir.
+// It contains a metadata file and the received chunks, the chunk file is named `<index>.<checksum>`.
+const (
+	uploadSessionDirPrefix = ".upload-"
+	uploadSessionMetaName  = "session.json"
+)
+
+// uploadSession is the metadata of upload session.
+type uploadSession struct {
+	SessionId string `json:"session_id"`
+	SpaceId   string `json:"space_id"`
+	FileId    string `json:"file_id"`
+	Version   string `json:"version"`
+	Size      int64  `json:"size"`
+	Created   int64  `json:"created"`
+	// The expected digest of file data, nil if not specified.
+	Expected *fileio.Digest `json:"expected,omitempty"`
+}
+
+func newUploadSessionId() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "ups-" + hex.EncodeToString(b), nil
+}
+
+func uploadChunkName(index int32, checksum string) string {
+	return fmt.Sprintf("%010d.%s", index, checksum)
+}
+
+func parseUploadChunkName(name string) (index int32, checksum string, ok bool) {
+	elems := strings.Split(path.Base(name), ".")
+	if len(elems) != 2 || len(elems[1]) != 32 {
+		return 0, "", false
+	}
+	i, err := strconv.ParseInt(elems[0], 10, 32)
+	if err != nil {
+		return 0, "", false
+	}
+	return int32(i), elems[1], true
+}
+
+func (x *StoreIo) generateUploadSessionDir(spaceId, fileId, sessionId string) string {
+	return x.generateResourceFileDir(spaceId, fileId) + "/" + uploadSessionDirPrefix + sessionId
+}
+
+// uploadSessionExpired returns the time that session will be expired after the last activity.
+func uploadSessionExpired(lastActivity time.Time) time.Time {
+	return lastActivity.Add(options.Config.Storage.UploadSessionTTL)
+}
+
+// loadUploadSession loads the session metadata and the received chunks sorted by index.
+// Returns ResourceNotExists if the session not exists or expired.
+func (x *StoreIo) loadUploadSession(ctx context.Context, fs fileio.FileIO, spaceId, fileId, sessionId string) (
+	session *uploadSession, chunks []*pbstoreio.UploadedChunk, expired time.Time, err error) {
+	lg := glog.FromContext(ctx)
+
+	sessionDir := x.generateUploadSessionDir(spaceId, fileId, sessionId)
+	infos, err := fs.List(ctx, sessionDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = qerror.ResourceNotExists.Format(sessionId)
+		}
+		return
+	}
+
+	var lastActivity time.Time
+	for _, info := range infos {
+		if info.ModTime.After(lastActivity) {
+			lastActivity = info.ModTime
+		}
+		if info.IsDir || fileio.IsStagingFile(info.Name) {
+			continue
+		}
+		if path.Base(info.Name) == uploadSessionMetaName {
+			session = new(uploadSession)
+			if err = readJSONFile(ctx, fs, info.Name, session); err != nil {
+				lg.Error().Msg("read upload session metadata failed").String("sessionId", sessionId).Error("error", err).Fire()
+				return
+			}
+			continue
+		}
+		if index, checksum, ok := parseUploadChunkName(info.Name); ok {
+			chunks = append(chunks, &pbstoreio.UploadedChunk{Index: index, Size: info.Size, Checksum: checksum})
+		}
+	}
+	// The metadata is missing if the session initialization interrupted.
+	if session == nil {
+		err = qerror.ResourceNotExists.Format(sessionId)
+		return
+	}
+	expired = uploadSessionExpired(lastActivity)
+	if time.Now().After(expired) {
+		err = qerror.ResourceNotExists.Format(sessionId)
+		return
+	}
+	return
+}
+
+// InitUploadSession creates a session to upload file data in multiple chunks.
+func (x *StoreIo) InitUploadSession(ctx context.Context, req *pbstoreio.InitUploadSessionRequest) (*pbstoreio.InitUploadSessionReply, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, err := x.withProxyUser(ctx, req.SpaceId)
+	if err != nil {
+		return nil, err
+	}
+	lg := glog.FromContext(ctx)
+	fs := x.storage(req.SpaceId)
+
+	filePath := x.generateResourceFilePath(req.SpaceId, req.FileId, req.Version)
+	exists, err := fs.IsExists(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, qerror.ResourceAlreadyExists.Format(req.Version)
+	}
+
+	sessionId, err := newUploadSessionId()
+	if err != nil {
+		return nil, err
+	}
+	session := &uploadSession{
+		SessionId: sessionId,
+		SpaceId:   req.SpaceId,
+		FileId:    req.FileId,
+		Version:   req.Version,
+		Size:      req.Size,
+		Created:   time.Now().Unix(),
+	}
+	if req.Md5 != "" || req.Sha256 != "" {
+		session.Expected = &fileio.Digest{MD5: req.Md5, SHA256: req.Sha256}
+	}
+	sessionDir := x.generateUploadSessionDir(req.SpaceId, req.FileId, sessionId)
+	if err = fs.MkdirAll(ctx, sessionDir, 0777); err != nil {
+		return nil, err
+	}
+	metaPath := sessionDir + "/" + uploadSessionMetaName
+	if err = writeJSONFile(ctx, fs, metaPath, session); err != nil {
+		_ = fs.RemoveAll(ctx, sessionDir)
+		return nil, err
+	}
+
+	lg.Info().Msg("upload session created").String("sessionId", sessionId).
+		String("version", req.Version).Int64("size", req.Size).Fire()
+	reply := &pbstoreio.InitUploadSessionReply{
+		SessionId: sessionId,
+		Expired:   uploadSessionExpired(time.Now()).Unix(),
+	}
+	return reply, nil
+}
+
+// UploadChunk stores a chunk of data in upload session. It is idempotent, the chunk
+// with same index will be replaced if the checksum is different.
+func (x *StoreIo) UploadChunk(ctx context.Context, req *pbstoreio.UploadChunkRequest) (reply *pbstoreio.UploadChunkReply, err error) {
+	if err = req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, end, err := beginTransfer(ctx, Transfer{Method: "UploadChunk", SpaceId: req.SpaceId, FileId: req.FileId, SessionId: req.SessionId})
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	defer func() {
+		err = drainError(ctx, err)
+	}()
+
+	if ctx, err = x.withProxyUser(ctx, req.SpaceId); err != nil {
+		return nil, err
+	}
+	lg := glog.FromContext(ctx)
+
+	sum := md5.Sum(req.Data)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), req.Checksum) {
+		checksumMismatches.WithLabelValues(mismatchSourceChunk).Inc()
+		return nil, qerror.InvalidRequest.Format(fmt.Sprintf("the checksum of chunk %d mismatch", req.Index))
+	}
+	checksum := strings.ToLower(req.Checksum)
+
+	fs := x.storage(req.SpaceId)
+	_, chunks, expired, err := x.loadUploadSession(ctx, fs, req.SpaceId, req.FileId, req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionDir := x.generateUploadSessionDir(req.SpaceId, req.FileId, req.SessionId)
+	for _, chunk := range chunks {
+		if chunk.Index != req.Index {
+			continue
+		}
+		if chunk.Checksum == checksum {
+			// The chunk has been received, may be the client retried after the reply lost.
+			return &pbstoreio.UploadChunkReply{Expired: expired.Unix()}, nil
+		}
+		lg.Info().Msg("replace the chunk in upload session").String("sessionId", req.SessionId).
+			Int32("index", req.Index).Fire()
+		if err = fs.Remove(ctx, sessionDir+"/"+uploadChunkName(chunk.Index, chunk.Checksum)); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	chunkPath := sessionDir + "/" + uploadChunkName(req.Index, checksum)
+	if _, err = fs.CreateAndWrite(ctx, chunkPath, io.NopCloser(bytes.NewReader(req.Data))); err != nil {
+		// The same chunk is uploaded concurrently.
+		if os.IsExist(err) {
+			return &pbstoreio.UploadChunkReply{Expired: expired.Unix()}, nil
+		}
+		return nil, err
+	}
+	transferBytes.WithLabelValues("upload").Add(float64(len(req.Data)))
+	return &pbstoreio.UploadChunkReply{Expired: uploadSessionExpired(time.Now()).Unix()}, nil
+}
+
+// QueryUploadSession returns the progress of upload session, the client should
+// upload the missing chunks to resume the upload.
+func (x *StoreIo) QueryUploadSession(ctx context.Context, req *pbstoreio.UploadSessionRequest) (*pbstoreio.QueryUploadSessionReply, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, err := x.withProxyUser(ctx, req.SpaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	session, chunks, expired, err := x.loadUploadSession(ctx, x.storage(req.SpaceId), req.SpaceId, req.FileId, req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+	reply := &pbstoreio.QueryUploadSessionReply{
+		Version: session.Version,
+		Size:    session.Size,
+		Expired: expired.Unix(),
+		Chunks:  chunks,
+	}
+	for _, chunk := range chunks {
+		reply.ReceivedSize += chunk.Size
+	}
+	return reply, nil
+}
+
+// CommitUploadSession concatenates all chunks to the resource file and removes the session.
+// It can be retried if the previous commit interrupted.
+func (x *StoreIo) CommitUploadSession(ctx context.Context, req *pbstoreio.UploadSessionRequest) (reply *pbstoreio.CommitUploadSessionReply, err error) {
+	if err = req.Validate(); err != nil {
+		return nil, err
+	}
+	ctx, end, err := beginTransfer(ctx, Transfer{Method: "CommitUploadSession", SpaceId: req.SpaceId, FileId: req.FileId, SessionId: req.SessionId})
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	defer func() {
+		err = drainError(ctx, err)
+	}()
+
+	if ctx, err = x.withProxyUser(ctx, req.SpaceId); err != nil {
+		return nil, err
+	}
+	lg := glog.FromContext(ctx)
+	fs := x.storage(req.SpaceId)
+
+	session, chunks, _, err := x.loadUploadSession(ctx, fs, req.SpaceId, req.FileId, req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionDir := x.generateUploadSessionDir(req.SpaceId, req.FileId, req.SessionId)
+	names := make([]string, 0, len(chunks))
+	var receivedSize int64
+	for i, chunk := range chunks {
+		if chunk.Index != int32(i) {
+			return nil, qerror.InvalidRequest.Format(fmt.Sprintf("the chunk %d is missing", i))
+		}
+		names = append(names, sessionDir+"/"+uploadChunkName(chunk.Index, chunk.Checksum))
+		receivedSize += chunk.Size
+	}
+	if receivedSize != session.Size {
+		return nil, qerror.InvalidRequest.Format(
+			fmt.Sprintf("the received size %d is not equal to file size %d", receivedSize, session.Size))
+	}
+
+	reader := &chunksReader{ctx: ctx, fs: fs, names: names}
+	digest, err := x.createFileData(ctx, fs, session.SpaceId, session.FileId, session.Version, reader, session.Expected)
+	_ = reader.Close()
+	if err != nil {
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		// The file may be published by previous commit that interrupted before removing the session.
+		filePath := x.generateResourceFilePath(session.SpaceId, session.FileId, session.Version)
+		info, serr := fs.Stat(ctx, filePath)
+		if serr != nil {
+			return nil, serr
+		}
+		if info.Size != session.Size {
+			return nil, qerror.ResourceAlreadyExists.Format(session.Version)
+		}
+		if digest, err = x.readDigest(ctx, fs, session.SpaceId, session.FileId, session.Version); err != nil {
+			return nil, err
+		}
+		if digest == nil {
+			digest = &fileio.Digest{MD5: info.ETag}
+		}
+	}
+	eTag := digest.MD5
+	observeUpload(session.Size)
+
+	if err = fs.RemoveAll(ctx, sessionDir); err != nil {
+		// The session will be removed after expired, so not return the error.
+		lg.Warn().Msg("remove upload session failed").String("sessionId", req.SessionId).Error("error", err).Fire()
+	}
+	lg.Info().Msg("upload session committed").String("sessionId", req.SessionId).
+		String("version", session.Version).String("eTag", eTag).Fire()
+	return &pbstoreio.CommitUploadSessionReply{Etag: eTag, Sha256: digest.SHA256}, nil
+}
+
+// chunksReader reads the chunk files one by one.
+type chunksReader struct {
+	ctx     context.Context
+	fs      fileio.FileIO
+	names   []string
+	current io.ReadCloser
+}
+
+func (r *chunksReader) Read(p []byte) (n int, err error) {
+	for {
+		// Abort the write if the commit cancelled.
+		if err = r.ctx.Err(); err != nil {
+			return 0, err
+		}
+		if r.current == nil {
+			if len(r.names) == 0 {
+				return 0, io.EOF
+			}
+			if r.current, err = r.fs.OpenForRead(r.ctx, r.names[0]); err != nil {
+				return 0, err
+			}
+			r.names = r.names[1:]
+		}
+		n, err = r.current.Read(p)
+		if err == io.EOF {
+			_ = r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (r *chunksReader) Close() error {
+	if r.current != nil {
+		err := r.current.Close()
+		r.current = nil
+		return err
+	}
+	return nil
+}
+
+// SweepUploadSessions removes the expired upload sessions in all storage backends.
+func SweepUploadSessions(ctx context.Context, ttl time.Duration) {
+	for _, name := range options.Storages.Names() {
+		fs, _ := options.Storages.Get(name)
+		sweepUploadSessions(ctx, name, fs, ttl)
+	}
+}
+
+func sweepUploadSessions(ctx context.Context, storage string, fs fileio.FileIO, ttl time.Duration) {
+	lg := glog.FromContext(ctx)
+	var removed int
+	err := fs.Walk(ctx, resourceRootDir, func(name string, info *fileio.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir || !strings.HasPrefix(path.Base(name), uploadSessionDirPrefix) {
+			return nil
+		}
+		infos, err := fs.List(ctx, name)
+		if err != nil {
+			return err
+		}
+		lastActivity := info.ModTime
+		for _, child := range infos {
+			if child.ModTime.After(lastActivity) {
+				lastActivity = child.ModTime
+			}
+		}
+		if time.Since(lastActivity) > ttl {
+			lg.Info().Msg("remove expired upload session").String("name", name).Fire()
+			if err = fs.RemoveAll(ctx, name); err != nil {
+				return err
+			}
+			removed++
+		}
+		return filepath.SkipDir
+	})
+	if err != nil && !os.IsNotExist(err) {
+		lg.Error().Msg("sweep expired upload sessions failed").String("storage", storage).Int("removed", removed).Error("error", err).Fire()
+		return
+	}
+	lg.Info().Msg("sweep expired upload sessions done").String("storage", storage).Int("removed", removed).Fire()
+}
+
+// RunUploadSessionSweeper sweeps the expired upload sessions periodically until ctx done.
+func RunUploadSessionSweeper(ctx context.Context, ttl time.Duration) {
+	ticker := time.NewTicker(ttl)
+	defer ticker.Stop()
+	for {
+		SweepUploadSessions(ctx, ttl)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataWorkbench/common/qerror"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+)
+
+func md5Hex(data string) string {
+	sum := md5.Sum([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUploadChunkName(t *testing.T) {
+	checksum := md5Hex("hello")
+	tests := []struct {
+		name     string
+		index    int32
+		checksum string
+		ok       bool
+	}{
+		{name: uploadChunkName(0, checksum), index: 0, checksum: checksum, ok: true},
+		{name: uploadChunkName(123, checksum), index: 123, checksum: checksum, ok: true},
+		{name: "/dir/" + uploadChunkName(7, checksum), index: 7, checksum: checksum, ok: true},
+		{name: uploadSessionMetaName},
+		{name: "0000000001.abc"},
+		{name: "x." + checksum},
+		{name: uploadChunkName(1, checksum) + ".staging-1"},
+	}
+	for _, tt := range tests {
+		index, checksum, ok := parseUploadChunkName(tt.name)
+		if ok != tt.ok || index != tt.index || checksum != tt.checksum {
+			t.Errorf("parseUploadChunkName(%q) = %d, %q, %v", tt.name, index, checksum, ok)
+		}
+	}
+}
+
+// initUploadSession creates the session to upload the version of test file with size.
+func initUploadSession(t *testing.T, x *StoreIo, size int64, md5 string) string {
+	t.Helper()
+	reply, err := x.InitUploadSession(testContext(), &pbstoreio.InitUploadSessionRequest{
+		SpaceId: testSpaceId, FileId: testFileId, Version: testVersion, Size: size, Md5: md5,
+	})
+	if err != nil {
+		t.Fatalf("init upload session: %v", err)
+	}
+	return reply.SessionId
+}
+
+func uploadChunk(x *StoreIo, sessionId string, index int32, data string) error {
+	_, err := x.UploadChunk(testContext(), &pbstoreio.UploadChunkRequest{
+		SpaceId: testSpaceId, FileId: testFileId, SessionId: sessionId, Index: index, Checksum: md5Hex(data), Data: []byte(data),
+	})
+	return err
+}
+
+func sessionRequest(sessionId string) *pbstoreio.UploadSessionRequest {
+	return &pbstoreio.UploadSessionRequest{SpaceId: testSpaceId, FileId: testFileId, SessionId: sessionId}
+}
+
+func TestUploadSession(t *testing.T) {
+	x := new(StoreIo)
+	fs := setupStorage(t, nil)
+	ctx := testContext()
+	sessionId := initUploadSession(t, x, 11, "")
+
+	chunks := []struct {
+		index int32
+		data  string
+	}{
+		{index: 1, data: "world"},
+		{index: 0, data: "hello "},
+		// The retried chunk is ignored.
+		{index: 0, data: "hello "},
+		// The chunk with different data replaces the previous one.
+		{index: 1, data: "WORLD"},
+	}
+	for _, chunk := range chunks {
+		if err := uploadChunk(x, sessionId, chunk.index, chunk.data); err != nil {
+			t.Fatalf("upload chunk %d: %v", chunk.index, err)
+		}
+	}
+
+	query, err := x.QueryUploadSession(ctx, sessionRequest(sessionId))
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if query.Version != testVersion || query.Size != 11 || query.ReceivedSize != 11 || len(query.Chunks) != 2 ||
+		query.Chunks[0].Index != 0 || query.Chunks[1].Checksum != md5Hex("WORLD") {
+		t.Fatalf("query = %v", query)
+	}
+
+	commit, err := x.CommitUploadSession(ctx, sessionRequest(sessionId))
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if commit.Etag != md5Hex("hello WORLD") || commit.Sha256 == "" {
+		t.Fatalf("commit = %v", commit)
+	}
+	stream, err := readFileData(x, testVersion, 0, 0)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if stream.data.String() != "hello WORLD" {
+		t.Fatalf("data = %q", stream.data.String())
+	}
+	sessionDir := x.generateUploadSessionDir(testSpaceId, testFileId, sessionId)
+	if exists, err := fs.IsExists(ctx, sessionDir); err != nil || exists {
+		t.Fatalf("session exists = %v, %v", exists, err)
+	}
+
+	if _, err = x.CommitUploadSession(ctx, sessionRequest(sessionId)); !isError(err, qerror.ResourceNotExists) {
+		t.Fatalf("commit again: %v", err)
+	}
+	_, err = x.InitUploadSession(ctx, &pbstoreio.InitUploadSessionRequest{
+		SpaceId: testSpaceId, FileId: testFileId, Version: testVersion, Size: 1,
+	})
+	if !isError(err, qerror.ResourceAlreadyExists) {
+		t.Fatalf("init exists: %v", err)
+	}
+}
+
+func TestUploadSessionFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int64
+		md5    string
+		chunks map[int32]string
+		ttl    time.Duration
+		upload *qerror.Error
+		commit *qerror.Error
+	}{
+		{
+			name:   "chunk missing",
+			size:   10,
+			chunks: map[int32]string{0: "hello", 2: "world"},
+			commit: qerror.InvalidRequest,
+		},
+		{
+			name:   "size mismatch",
+			size:   11,
+			chunks: map[int32]string{0: "hello", 1: "world"},
+			commit: qerror.InvalidRequest,
+		},
+		{
+			name:   "digest mismatch",
+			size:   10,
+			md5:    md5Hex("other"),
+			chunks: map[int32]string{0: "hello", 1: "world"},
+			commit: qerror.InvalidRequest,
+		},
+		{
+			name:   "expired",
+			size:   5,
+			chunks: map[int32]string{0: "hello"},
+			ttl:    -time.Second,
+			upload: qerror.ResourceNotExists,
+			commit: qerror.ResourceNotExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := new(StoreIo)
+			fs := setupStorage(t, func(cfg *config.Config) {
+				if tt.ttl != 0 {
+					cfg.Storage.UploadSessionTTL = tt.ttl
+				}
+			})
+			sessionId := initUploadSession(t, x, tt.size, tt.md5)
+			for index, data := range tt.chunks {
+				err := uploadChunk(x, sessionId, index, data)
+				if tt.upload != nil {
+					if !isError(err, tt.upload) {
+						t.Fatalf("upload error = %v, want %v", err, tt.upload)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("upload chunk %d: %v", index, err)
+				}
+			}
+			_, err := x.CommitUploadSession(testContext(), sessionRequest(sessionId))
+			if !isError(err, tt.commit) {
+				t.Fatalf("commit error = %v, want %v", err, tt.commit)
+			}
+			filePath := x.generateResourceFilePath(testSpaceId, testFileId, testVersion)
+			if exists, err := fs.IsExists(testContext(), filePath); err != nil || exists {
+				t.Fatalf("file exists = %v, %v", exists, err)
+			}
+		})
+	}
+}
+
+func TestUploadChunkChecksumMismatch(t *testing.T) {
+	x := new(StoreIo)
+	setupStorage(t, nil)
+	sessionId := initUploadSession(t, x, 5, "")
+	_, err := x.UploadChunk(testContext(), &pbstoreio.UploadChunkRequest{
+		SpaceId: testSpaceId, FileId: testFileId, SessionId: sessionId, Index: 0,
+		Checksum: strings.ToUpper(md5Hex("other")), Data: []byte("hello"),
+	})
+	if !isError(err, qerror.InvalidRequest) {
+		t.Fatalf("error = %v, want %v", err, qerror.InvalidRequest)
+	}
+	// The checksum is case-insensitive.
+	_, err = x.UploadChunk(testContext(), &pbstoreio.UploadChunkRequest{
+		SpaceId: testSpaceId, FileId: testFileId, SessionId: sessionId, Index: 0,
+		Checksum: strings.ToUpper(md5Hex("hello")), Data: []byte("hello"),
+	})
+	if err != nil {
+		t.Fatalf("upload chunk: %v", err)
+	}
+}
+
+func TestSweepUploadSessions(t *testing.T) {
+	tests := []struct {
+		name   string
+		ttl    time.Duration
+		exists bool
+	}{
+		{name: "active", ttl: time.Hour, exists: true},
+		{name: "expired", ttl: 0, exists: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := new(StoreIo)
+			fs := setupStorage(t, nil)
+			sessionId := initUploadSession(t, x, 5, "")
+			time.Sleep(time.Millisecond)
+
+			SweepUploadSessions(testContext(), tt.ttl)
+			sessionDir := x.generateUploadSessionDir(testSpaceId, testFileId, sessionId)
+			if exists, err := fs.IsExists(testContext(), sessionDir); err != nil || exists != tt.exists {
+				t.Fatalf("session exists = %v, %v, want %v", exists, err, tt.exists)
+			}
+		})
+	}
+}
@@ -11,6 +11,13 @@ require (
 	github.com/aws/aws-sdk-go v1.43.7
 	github.com/colinmarc/hdfs/v2 v2.2.0
 	github.com/go-playground/validator/v10 v10.10.0
+	github.com/jcmturner/gokrb5/v8 v8.4.2
+	github.com/opentracing/opentracing-go v1.1.0
+	github.com/prometheus/client_golang v1.11.0
 	github.com/spf13/cobra v1.2.1
+	github.com/yu31/protoc-plugin v0.0.0-20220204051042-6ae48e54d91b
+	google.golang.org/grpc v1.44.0
+	google.golang.org/protobuf v1.27.1
+	gopkg.in/natefinch/lumberjack.v2 v2.0.0
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b
 )
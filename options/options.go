@@ -2,8 +2,11 @@ package options
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 
 	"github.com/DataWorkbench/common/grpcwrap"
+	"github.com/DataWorkbench/common/gtrace"
 	"github.com/DataWorkbench/glog"
 	"github.com/DataWorkbench/gproto/xgo/types/pbmodel"
 	"github.com/DataWorkbench/resourcemanager/config"
@@ -14,31 +17,121 @@ var EmptyRPCReply = &pbmodel.EmptyStruct{}
 
 var (
 	Config *config.Config
-	FiloIO fileio.FileIO
+	// Storages holds all storage backends and routes the workspaces to them.
+	Storages *fileio.Registry
+
+	// The clients and read caches of storage backends by name, they can be changed by reload.
+	clients = make(map[string]*fileio.Swappable)
+	caches  = make(map[string]*fileio.Cache)
 )
 
 func Init(ctx context.Context, cfg *config.Config) (err error) {
 	Config = cfg
-	_ = Config
 
 	// Set grpc logger.
 	grpcwrap.SetLogger(glog.FromContext(ctx), cfg.GRPCLog)
 
-	switch cfg.Storage.Background {
+	Storages = fileio.NewRegistry()
+	// Close the backends already created if any one failed, so they are not leaked.
+	defer func() {
+		if err != nil {
+			_ = Storages.Close()
+			Storages = nil
+			clients = make(map[string]*fileio.Swappable)
+			caches = make(map[string]*fileio.Cache)
+		}
+	}()
+	for _, name := range cfg.StorageNames() {
+		var fs fileio.FileIO
+		if fs, err = NewFileIO(ctx, cfg.GetStorage(name)); err != nil {
+			return
+		}
+		client := fileio.NewSwappable(fs)
+		clients[name] = client
+		fs = fileio.NewInstrumented(fileio.NewTraced(client, name, gtrace.TracerFromContext(ctx)), name)
+		if err = Storages.Register(name, fs); err != nil {
+			_ = fs.Close()
+			return
+		}
+		if cfg.ReadCache.Enabled {
+			var cache *fileio.Cache
+			dir := filepath.Join(cfg.ReadCache.Dir, name)
+			if cache, err = fileio.NewCache(ctx, fs, dir, cfg.ReadCache.Capacity*1024*1024, cfg.ReadCache.RevalidateInterval); err != nil {
+				return
+			}
+			if err = Storages.SetReadCache(name, cache); err != nil {
+				return
+			}
+			caches[name] = cache
+		}
+	}
+	if err = Storages.SetRoutes(cfg.Routing.Default, cfg.Routing.Workspaces); err != nil {
+		return
+	}
+	for name, fallback := range cfg.Routing.Fallbacks {
+		if err = Storages.SetFallback(name, fallback); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// NewFileIO creates the FileIO by storage background.
+func NewFileIO(ctx context.Context, st *config.Storage) (fs fileio.FileIO, err error) {
+	switch st.Background {
 	case config.StorageBackgroundHDFS:
-		FiloIO, err = fileio.NewHadoopClientFromConfFile(ctx, cfg.Storage.HadoopConfDir, "root")
+		fs, err = fileio.NewHadoopClientFromConfFile(ctx, st.HadoopConfDir, st.HadoopUser,
+			st.Kerberos, st.HadoopClientPoolSize)
 	case config.StorageBackgroundS3:
-		FiloIO, err = fileio.NewS3Client(ctx, cfg.Storage.S3)
+		fs, err = fileio.NewS3Client(ctx, st.S3)
+	case config.StorageBackgroundLocal:
+		fs, err = fileio.NewLocal(ctx, st.LocalRootDir)
+	case config.StorageBackgroundMemory:
+		fs, err = fileio.NewMemory(ctx, st.MemoryCapacity)
 	}
+	return
+}
+
+// ReloadStorage creates a new client by st and swaps it into the named backend. The operations
+// in flight finish on the previous client, which is closed after them in background.
+func ReloadStorage(ctx context.Context, name string, st *config.Storage) error {
+	lg := glog.FromContext(ctx)
+
+	client, ok := clients[name]
+	if !ok {
+		return fmt.Errorf("storage <%s> not registered", name)
+	}
+	if st.Background == config.StorageBackgroundMemory {
+		return fmt.Errorf("storage <%s>: the data in memory would be lost by swapping client", name)
+	}
+	fs, err := NewFileIO(ctx, st)
 	if err != nil {
-		return
+		return err
 	}
-	return
+	done := client.Swap(fs)
+	go func() {
+		if err := <-done; err != nil {
+			lg.Warn().Msg("close the previous storage client failed").String("storage", name).Error("error", err).Fire()
+			return
+		}
+		lg.Info().Msg("the previous storage client closed").String("storage", name).Fire()
+	}()
+	return nil
+}
+
+// SetReadCacheCapacity changes the capacity in MiB of the read cache of each backend.
+func SetReadCacheCapacity(capacity int64) error {
+	for _, cache := range caches {
+		if err := cache.SetCapacity(capacity * 1024 * 1024); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func Close() (err error) {
-	if FiloIO != nil {
-		_ = FiloIO.Close()
+	if Storages != nil {
+		_ = Storages.Close()
 	}
 	return
 }
@@ -0,0 +1,58 @@
+package options
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataWorkbench/common/grpcwrap"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+)
+
+func TestInitFailed(t *testing.T) {
+	// The root dir of local storage can not be created under a file.
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0666); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	tests := []struct {
+		name string
+		cfg  *config.Config
+	}{
+		{
+			name: "storage",
+			cfg: &config.Config{
+				Storages: map[string]*config.Storage{
+					"archive": {Background: config.StorageBackgroundLocal, LocalRootDir: filepath.Join(file, "root")},
+				},
+			},
+		},
+		{
+			name: "routing",
+			cfg: &config.Config{
+				Routing: &config.Routing{Default: "missing"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.GRPCLog = &grpcwrap.LogConfig{Level: 4, Verbosity: 99}
+			cfg.Storage = &config.Storage{Background: config.StorageBackgroundMemory}
+			cfg.ReadCache = &config.ReadCache{}
+			if cfg.Routing == nil {
+				cfg.Routing = &config.Routing{Default: config.DefaultStorageName}
+			}
+			ctx := glog.WithContext(context.Background(), glog.NewDefault().WithLevel(glog.FatalLevel))
+
+			if err := Init(ctx, cfg); err == nil {
+				t.Fatal("expected error")
+			}
+			if Storages != nil || len(clients) != 0 || len(caches) != 0 {
+				t.Fatalf("storages = %v, %d clients and %d caches left", Storages, len(clients), len(caches))
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package fileio
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataWorkbench/glog"
+)
+
+// cacheTempPrefix is the name prefix of the file that data is being loaded into.
+const cacheTempPrefix = ".tmp-"
+
+// CacheStats is the statistics of Cache.
+type CacheStats struct {
+	// The number of reads served from local disk.
+	Hits int64
+	// The number of reads that loaded data from storage.
+	Misses int64
+	// The number of files and total bytes in cache.
+	Files int
+	Size  int64
+}
+
+// Cache is a FileIO that keeps the recently read files on local disk, the least recently
+// used files are evicted when the total size exceeds the capacity. The files are keyed by
+// path plus eTag, or size and modification time if the storage does not known eTag, so the
+// modified file will not be served from the stale cache. The file is checked by Stat at most
+// once per revalidate interval, the hits within it are served without accessing storage.
+// The concurrent misses for the same file are coalesced into one load from storage.
+//
+// Only OpenForRead populates the cache, OpenRange is served from the cache if hit.
+// The other methods are delegated to the underlying FileIO directly.
+type Cache struct {
+	FileIO
+
+	dir        string
+	capacity   int64
+	revalidate time.Duration
+
+	hits   int64
+	misses int64
+
+	mu      sync.Mutex
+	lru     *list.List // *cacheEntry, the front is the most recently used.
+	entries map[string]*list.Element
+	names   map[string]*list.Element // The entry of the latest version by name.
+	size    int64
+	loading map[string]*cacheCall
+}
+
+var _ FileIO = (*Cache)(nil)
+
+type cacheEntry struct {
+	key  string
+	size int64
+	// The name and the time it was validated by Stat, empty for the entries restored at startup.
+	name      string
+	validated time.Time
+}
+
+// cacheCall is an in-flight load that the concurrent misses wait for.
+type cacheCall struct {
+	wg  sync.WaitGroup
+	err error
+}
+
+// NewCache creates a Cache that stores files in dir with the max total bytes of capacity.
+// The cached file is checked again by Stat after revalidate, 0 means check on every read.
+// The files left in dir by previous process are reused.
+func NewCache(ctx context.Context, fs FileIO, dir string, capacity int64, revalidate time.Duration) (*Cache, error) {
+	lg := glog.FromContext(ctx)
+
+	if capacity <= 0 {
+		return nil, fmt.Errorf("fileio: invalid cache capacity %d", capacity)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	c := &Cache{
+		FileIO:     fs,
+		dir:        dir,
+		capacity:   capacity,
+		revalidate: revalidate,
+		lru:        list.New(),
+		entries:    make(map[string]*list.Element),
+		names:      make(map[string]*list.Element),
+		loading:    make(map[string]*cacheCall),
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]os.FileInfo, 0, len(dirEntries))
+	for _, entry := range dirEntries {
+		if entry.IsDir() {
+			continue
+		}
+		// The incomplete file left by the process that crashed when loading.
+		if strings.HasPrefix(entry.Name(), cacheTempPrefix) {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	// Restore the order by modification time, the oldest is evicted first.
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+	c.mu.Lock()
+	for _, info := range infos {
+		c.add(info.Name(), info.Size())
+	}
+	c.mu.Unlock()
+
+	lg.Info().Msg("cache: initialized").String("dir", dir).Int64("capacity", capacity).
+		Int("files", c.lru.Len()).Int64("size", c.size).Fire()
+	return c, nil
+}
+
+// Stats returns the statistics of cache.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Hits:   atomic.LoadInt64(&c.hits),
+		Misses: atomic.LoadInt64(&c.misses),
+		Files:  c.lru.Len(),
+		Size:   c.size,
+	}
+}
+
+// SetCapacity changes the max total bytes of cache, the least recently used files
+// are evicted if the total size exceeds the new capacity.
+func (c *Cache) SetCapacity(capacity int64) error {
+	if capacity <= 0 {
+		return fmt.Errorf("fileio: invalid cache capacity %d", capacity)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capacity = capacity
+	c.evict()
+	return nil
+}
+
+func (c *Cache) cacheKey(name string, info *FileInfo) string {
+	version := info.ETag
+	if version == "" {
+		version = strconv.FormatInt(info.Size, 10) + "-" + strconv.FormatInt(info.ModTime.UnixNano(), 10)
+	}
+	sum := sha256.Sum256([]byte(name + "\x00" + version))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key)
+}
+
+// OpenForRead opens the file from cache, loads it from storage if not cached.
+// The file is read from storage directly if it is larger than capacity or failed to cache.
+func (c *Cache) OpenForRead(ctx context.Context, name string) (io.ReadCloser, error) {
+	lg := glog.FromContext(ctx)
+
+	if f, _ := c.lookup(name); f != nil {
+		atomic.AddInt64(&c.hits, 1)
+		return f, nil
+	}
+	info, err := c.FileIO.Stat(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	capacity := c.capacity
+	c.mu.Unlock()
+	if info.IsDir || info.Size > capacity {
+		return c.FileIO.OpenForRead(ctx, name)
+	}
+	key := c.cacheKey(name, info)
+	if f := c.open(key, name); f != nil {
+		atomic.AddInt64(&c.hits, 1)
+		return f, nil
+	}
+
+	atomic.AddInt64(&c.misses, 1)
+	if err = c.load(ctx, name, key, info.Size); err != nil {
+		lg.Warn().Msg("cache: load file failed, read from storage").String("name", name).Error("error", err).Fire()
+		return c.FileIO.OpenForRead(ctx, name)
+	}
+	if f := c.open(key, name); f != nil {
+		return f, nil
+	}
+	// Evicted by the others right after loaded.
+	return c.FileIO.OpenForRead(ctx, name)
+}
+
+// OpenRange reads the range from cache if the file is cached, or from storage otherwise.
+func (c *Cache) OpenRange(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	f, size := c.lookup(name)
+	if f == nil {
+		info, err := c.FileIO.Stat(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir {
+			return c.FileIO.OpenRange(ctx, name, offset, length)
+		}
+		if f = c.open(c.cacheKey(name, info), name); f == nil {
+			return c.FileIO.OpenRange(ctx, name, offset, length)
+		}
+		size = info.Size
+	}
+	atomic.AddInt64(&c.hits, 1)
+	if offset > size {
+		_ = f.Close()
+		return nil, ErrInvalidRange
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return limitRange(f, length), nil
+}
+
+// lookup returns the cached file of name and its size if it was validated within the revalidate
+// interval. Returns nil if not cached or it must be validated again.
+func (c *Cache) lookup(name string) (*os.File, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.names[name]
+	if !ok {
+		return nil, 0
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Since(entry.validated) >= c.revalidate {
+		return nil, 0
+	}
+	return c.openLocked(elem), entry.size
+}
+
+// open returns the cached file that validated for name just now, and marks it as most recently used.
+// Returns nil if not cached.
+func (c *Cache) open(key string, name string) *os.File {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	f := c.openLocked(elem)
+	if f == nil {
+		return nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if prev, ok := c.names[name]; ok && prev != elem {
+		// The previous version of name.
+		prev.Value.(*cacheEntry).name = ""
+	}
+	entry.name = name
+	entry.validated = time.Now()
+	c.names[name] = elem
+	return f
+}
+
+// openLocked opens the file of entry and marks it as most recently used. Returns nil if the file is
+// removed. It must be called with c.mu held, so the file will not be evicted before opened.
+func (c *Cache) openLocked(elem *list.Element) *os.File {
+	f, err := os.Open(c.path(elem.Value.(*cacheEntry).key))
+	if err != nil {
+		// Removed by others, such as the administrator.
+		c.remove(elem)
+		return nil
+	}
+	c.lru.MoveToFront(elem)
+	return f
+}
+
+// load copies the file from storage to cache. The concurrent loads of the same key wait for the first one.
+func (c *Cache) load(ctx context.Context, name, key string, size int64) error {
+	c.mu.Lock()
+	if _, ok := c.entries[key]; ok {
+		c.mu.Unlock()
+		return nil
+	}
+	if call, ok := c.loading[key]; ok {
+		c.mu.Unlock()
+		call.wg.Wait()
+		return call.err
+	}
+	call := new(cacheCall)
+	call.wg.Add(1)
+	c.loading[key] = call
+	c.mu.Unlock()
+
+	call.err = c.fetch(ctx, name, key, size)
+
+	c.mu.Lock()
+	delete(c.loading, key)
+	if call.err == nil {
+		c.add(key, size)
+	}
+	c.mu.Unlock()
+	call.wg.Done()
+	return call.err
+}
+
+// fetch writes the data to a temporary file first, then renames it to the key.
+func (c *Cache) fetch(ctx context.Context, name, key string, size int64) (err error) {
+	reader, err := c.FileIO.OpenForRead(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	tmp, err := os.CreateTemp(c.dir, cacheTempPrefix)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	n, err := io.Copy(tmp, reader)
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("fileio: the file size changed from %d to %d when caching", size, n)
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// add inserts the entry and evicts the least recently used entries. It must be called with c.mu held.
+func (c *Cache) add(key string, size int64) {
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, size: size})
+	c.size += size
+	c.evict()
+}
+
+// evict removes the least recently used entries until the total size not exceeds
+// capacity, the most recently used is kept. It must be called with c.mu held.
+func (c *Cache) evict() {
+	for c.size > c.capacity && c.lru.Len() > 1 {
+		back := c.lru.Back()
+		c.remove(back)
+		_ = os.Remove(c.path(back.Value.(*cacheEntry).key))
+	}
+}
+
+// remove deletes the entry from index. It must be called with c.mu held.
+func (c *Cache) remove(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, entry.key)
+	if entry.name != "" {
+		delete(c.names, entry.name)
+	}
+	c.size -= entry.size
+}
@@ -0,0 +1,291 @@
+package fileio
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingFS counts the OpenForRead and Stat of each file, the opens are blocked until gate closed if it is not nil.
+type countingFS struct {
+	FileIO
+	gate chan struct{}
+
+	mu    sync.Mutex
+	opens map[string]int
+	stats map[string]int
+}
+
+func (fs *countingFS) Stat(ctx context.Context, name string) (*FileInfo, error) {
+	fs.mu.Lock()
+	fs.stats[name]++
+	fs.mu.Unlock()
+	return fs.FileIO.Stat(ctx, name)
+}
+
+func (fs *countingFS) OpenForRead(ctx context.Context, name string) (io.ReadCloser, error) {
+	fs.mu.Lock()
+	fs.opens[name]++
+	fs.mu.Unlock()
+	if fs.gate != nil {
+		<-fs.gate
+	}
+	return fs.FileIO.OpenForRead(ctx, name)
+}
+
+func (fs *countingFS) count(name string) int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.opens[name]
+}
+
+func (fs *countingFS) statCount(name string) int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.stats[name]
+}
+
+// newTestCache returns a Cache with capacity over a memory storage that has the files.
+func newTestCache(t *testing.T, capacity int64, files map[string]string) (*Cache, *countingFS) {
+	t.Helper()
+	m := newTestMemory(t, 0)
+	for name, data := range files {
+		writeString(t, m, name, data)
+	}
+	fs := &countingFS{FileIO: m, opens: make(map[string]int), stats: make(map[string]int)}
+	c, err := NewCache(testContext(), fs, t.TempDir(), capacity, 0)
+	if err != nil {
+		t.Fatalf("new cache: %v", err)
+	}
+	return c, fs
+}
+
+func TestNewCacheInvalidCapacity(t *testing.T) {
+	if _, err := NewCache(testContext(), newTestMemory(t, 0), t.TempDir(), 0, 0); err == nil {
+		t.Fatal("expected error for zero capacity")
+	}
+}
+
+func TestCacheLRU(t *testing.T) {
+	files := map[string]string{
+		"/dir/a":     "aaaa",
+		"/dir/b":     "bbbb",
+		"/dir/c":     "cccc",
+		"/dir/large": "01234567890",
+	}
+	c, fs := newTestCache(t, 10, files)
+	tests := []struct {
+		name  string
+		opens int // The opens of storage after read.
+		files int
+		size  int64
+	}{
+		{name: "/dir/a", opens: 1, files: 1, size: 4},
+		{name: "/dir/a", opens: 1, files: 1, size: 4},
+		{name: "/dir/b", opens: 1, files: 2, size: 8},
+		// The a is the most recently used now.
+		{name: "/dir/a", opens: 1, files: 2, size: 8},
+		// The b is evicted.
+		{name: "/dir/c", opens: 1, files: 2, size: 8},
+		{name: "/dir/a", opens: 1, files: 2, size: 8},
+		{name: "/dir/b", opens: 2, files: 2, size: 8},
+		// The file larger than capacity is not cached.
+		{name: "/dir/large", opens: 1, files: 2, size: 8},
+		{name: "/dir/large", opens: 2, files: 2, size: 8},
+	}
+	for i, tt := range tests {
+		if got := readString(t, c, tt.name); got != files[tt.name] {
+			t.Fatalf("%d: read %s = %q, want %q", i, tt.name, got, files[tt.name])
+		}
+		stats := c.Stats()
+		if fs.count(tt.name) != tt.opens || stats.Files != tt.files || stats.Size != tt.size {
+			t.Fatalf("%d: read %s: opens = %d, stats = %+v", i, tt.name, fs.count(tt.name), stats)
+		}
+	}
+	if stats := c.Stats(); stats.Hits != 3 || stats.Misses != 4 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	if err := c.SetCapacity(0); err == nil {
+		t.Fatal("expected error for zero capacity")
+	}
+	if err := c.SetCapacity(5); err != nil {
+		t.Fatalf("set capacity: %v", err)
+	}
+	if stats := c.Stats(); stats.Files != 1 || stats.Size != 4 {
+		t.Fatalf("stats after shrink = %+v", stats)
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("files in cache dir = %d, %v", len(entries), err)
+	}
+}
+
+func TestCacheModifiedFile(t *testing.T) {
+	c, fs := newTestCache(t, 100, map[string]string{"/dir/a": "old"})
+	ctx := testContext()
+	readString(t, c, "/dir/a")
+	if err := c.Remove(ctx, "/dir/a"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	writeString(t, c, "/dir/a", "new")
+	if got := readString(t, c, "/dir/a"); got != "new" {
+		t.Fatalf("read = %q, want new", got)
+	}
+	if fs.count("/dir/a") != 2 {
+		t.Fatalf("opens = %d, want 2", fs.count("/dir/a"))
+	}
+}
+
+func TestCacheRevalidate(t *testing.T) {
+	c, fs := newTestCache(t, 100, map[string]string{"/dir/a": "old"})
+	c.revalidate = time.Hour
+	ctx := testContext()
+	readString(t, c, "/dir/a")
+
+	// The hits within the revalidate interval are served without Stat, even if modified in storage.
+	if err := c.Remove(ctx, "/dir/a"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	writeString(t, c, "/dir/a", "new")
+	if got := readString(t, c, "/dir/a"); got != "old" {
+		t.Fatalf("read = %q, want old", got)
+	}
+	reader, err := c.OpenRange(ctx, "/dir/a", 1, 1)
+	if err != nil {
+		t.Fatalf("open range: %v", err)
+	}
+	data, _ := io.ReadAll(reader)
+	_ = reader.Close()
+	if string(data) != "l" || fs.statCount("/dir/a") != 1 {
+		t.Fatalf("range = %q, stats = %d", data, fs.statCount("/dir/a"))
+	}
+	if _, err = c.OpenRange(ctx, "/dir/a", 4, 1); err != ErrInvalidRange {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidRange)
+	}
+
+	// Revalidated after the interval.
+	c.revalidate = 0
+	if got := readString(t, c, "/dir/a"); got != "new" {
+		t.Fatalf("read = %q, want new", got)
+	}
+	if fs.statCount("/dir/a") != 2 || fs.count("/dir/a") != 2 {
+		t.Fatalf("stats = %d, opens = %d", fs.statCount("/dir/a"), fs.count("/dir/a"))
+	}
+	// The entry of old version is kept until evicted, but no longer served by name.
+	if _, ok := c.names["/dir/a"]; !ok || c.Stats().Files != 2 {
+		t.Fatalf("names = %v, stats = %+v", c.names, c.Stats())
+	}
+}
+
+func TestCacheOpenRange(t *testing.T) {
+	c, fs := newTestCache(t, 100, map[string]string{"/dir/a": "0123456789"})
+	tests := []struct {
+		name   string
+		cached bool
+		offset int64
+		length int64
+		want   string
+		err    error
+	}{
+		{name: "not cached", offset: 2, length: 3, want: "234"},
+		{name: "cached", cached: true, offset: 2, length: 3, want: "234"},
+		{name: "cached to end", cached: true, offset: 7, want: "789"},
+		{name: "cached beyond end", cached: true, offset: 11, err: ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cached {
+				readString(t, c, "/dir/a")
+			}
+			opens := fs.count("/dir/a")
+			hits := c.Stats().Hits
+			reader, err := c.OpenRange(testContext(), "/dir/a", tt.offset, tt.length)
+			if tt.err != nil {
+				if err != tt.err {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("open range: %v", err)
+			}
+			data, _ := io.ReadAll(reader)
+			_ = reader.Close()
+			if string(data) != tt.want {
+				t.Fatalf("data = %q, want %q", data, tt.want)
+			}
+			// The range read never loads the file, and only counted as hit if cached.
+			if fs.count("/dir/a") != opens || (c.Stats().Hits > hits) != tt.cached {
+				t.Fatalf("opens = %d, stats = %+v", fs.count("/dir/a"), c.Stats())
+			}
+		})
+	}
+}
+
+func TestCacheCoalesceMisses(t *testing.T) {
+	c, fs := newTestCache(t, 100, map[string]string{"/dir/a": "hello"})
+	fs.gate = make(chan struct{})
+
+	const readers = 8
+	var wg sync.WaitGroup
+	results := make([]string, readers)
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reader, err := c.OpenForRead(testContext(), "/dir/a")
+			if err != nil {
+				results[i] = err.Error()
+				return
+			}
+			data, _ := io.ReadAll(reader)
+			_ = reader.Close()
+			results[i] = string(data)
+		}(i)
+	}
+	// Wait for all readers missed, then let the load finish.
+	for c.Stats().Misses != readers {
+		runtime.Gosched()
+	}
+	close(fs.gate)
+	wg.Wait()
+
+	for i, result := range results {
+		if result != "hello" {
+			t.Fatalf("reader %d = %q", i, result)
+		}
+	}
+	if fs.count("/dir/a") != 1 {
+		t.Fatalf("opens = %d, want 1", fs.count("/dir/a"))
+	}
+}
+
+func TestNewCacheReuseFiles(t *testing.T) {
+	c, fs := newTestCache(t, 100, map[string]string{"/dir/a": "hello"})
+	readString(t, c, "/dir/a")
+	// The incomplete file is removed.
+	tmp := filepath.Join(c.dir, cacheTempPrefix+"1")
+	if err := os.WriteFile(tmp, []byte("hel"), 0644); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+
+	reopened, err := NewCache(testContext(), fs, c.dir, 100, 0)
+	if err != nil {
+		t.Fatalf("new cache: %v", err)
+	}
+	if stats := reopened.Stats(); stats.Files != 1 || stats.Size != 5 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if _, err = os.Stat(tmp); !os.IsNotExist(err) {
+		t.Fatalf("the temp file is not removed: %v", err)
+	}
+	if got := readString(t, reopened, "/dir/a"); got != "hello" || fs.count("/dir/a") != 1 {
+		t.Fatalf("read = %q, opens = %d", got, fs.count("/dir/a"))
+	}
+}
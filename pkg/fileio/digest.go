@@ -0,0 +1,106 @@
+package fileio
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"strings"
+)
+
+// ErrChecksumMismatch is returned when the digest of data is not equal to the expected.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Digest holds the checksums of file data as hex. It is same for all backends.
+type Digest struct {
+	MD5    string `json:"md5"`
+	SHA256 string `json:"sha256"`
+}
+
+// Match reports whether the digest matches the expected. The empty field in expected is ignored.
+func (d *Digest) Match(expected *Digest) bool {
+	if expected == nil {
+		return true
+	}
+	if expected.MD5 != "" && !strings.EqualFold(d.MD5, expected.MD5) {
+		return false
+	}
+	if expected.SHA256 != "" && !strings.EqualFold(d.SHA256, expected.SHA256) {
+		return false
+	}
+	return true
+}
+
+// DigestReader computes the digest of data that read through it.
+//
+// If the expected digest specified, it returns ErrChecksumMismatch instead of io.EOF
+// at the end of data when mismatch. So the CreateAndWrite will abort and publish nothing.
+type DigestReader struct {
+	reader     io.ReadCloser
+	md5        hash.Hash
+	sha256     hash.Hash
+	size       int64
+	expected   *Digest
+	mismatched bool
+}
+
+// NewDigestReader returns a DigestReader that reads from reader. The expected can be nil.
+func NewDigestReader(reader io.ReadCloser, expected *Digest) *DigestReader {
+	return &DigestReader{
+		reader:   reader,
+		md5:      md5.New(),
+		sha256:   sha256.New(),
+		expected: expected,
+	}
+}
+
+func (r *DigestReader) Read(p []byte) (n int, err error) {
+	if r.mismatched {
+		return 0, ErrChecksumMismatch
+	}
+	n, err = r.reader.Read(p)
+	if n > 0 {
+		r.md5.Write(p[:n])
+		r.sha256.Write(p[:n])
+		r.size += int64(n)
+	}
+	if err == io.EOF && !r.Digest().Match(r.expected) {
+		r.mismatched = true
+		err = ErrChecksumMismatch
+	}
+	return n, err
+}
+
+func (r *DigestReader) Close() error {
+	return r.reader.Close()
+}
+
+// Digest returns the digest of data that has been read.
+func (r *DigestReader) Digest() *Digest {
+	return &Digest{
+		MD5:    hex.EncodeToString(r.md5.Sum(nil)),
+		SHA256: hex.EncodeToString(r.sha256.Sum(nil)),
+	}
+}
+
+// Size returns the bytes of data that has been read.
+func (r *DigestReader) Size() int64 {
+	return r.size
+}
+
+// Mismatched reports whether the data mismatches the expected digest.
+// Because some backends wrap the error of reader, use it instead of comparing error.
+func (r *DigestReader) Mismatched() bool {
+	return r.mismatched
+}
+
+// ComputeDigest reads all data from reader and returns its digest and size.
+func ComputeDigest(reader io.Reader) (*Digest, int64, error) {
+	r := NewDigestReader(io.NopCloser(reader), nil)
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return nil, 0, err
+	}
+	return r.Digest(), r.Size(), nil
+}
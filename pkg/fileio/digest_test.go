@@ -0,0 +1,95 @@
+package fileio
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+const (
+	helloMD5    = "5d41402abc4b2a76b9719d911017c592"
+	helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+)
+
+func TestDigestMatch(t *testing.T) {
+	digest := &Digest{MD5: helloMD5, SHA256: helloSHA256}
+	tests := []struct {
+		name     string
+		expected *Digest
+		want     bool
+	}{
+		{name: "nil", expected: nil, want: true},
+		{name: "empty", expected: &Digest{}, want: true},
+		{name: "md5", expected: &Digest{MD5: helloMD5}, want: true},
+		{name: "sha256", expected: &Digest{SHA256: helloSHA256}, want: true},
+		{name: "both", expected: &Digest{MD5: helloMD5, SHA256: helloSHA256}, want: true},
+		{name: "upper case", expected: &Digest{MD5: strings.ToUpper(helloMD5)}, want: true},
+		{name: "md5 mismatch", expected: &Digest{MD5: strings.Repeat("0", 32)}, want: false},
+		{name: "sha256 mismatch", expected: &Digest{MD5: helloMD5, SHA256: strings.Repeat("0", 64)}, want: false},
+	}
+	for _, tt := range tests {
+		if got := digest.Match(tt.expected); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDigestReader(t *testing.T) {
+	tests := []struct {
+		name       string
+		expected   *Digest
+		mismatched bool
+	}{
+		{name: "no expected"},
+		{name: "matched", expected: &Digest{MD5: helloMD5, SHA256: helloSHA256}},
+		{name: "mismatched", expected: &Digest{MD5: strings.Repeat("0", 32)}, mismatched: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewDigestReader(io.NopCloser(strings.NewReader("hello")), tt.expected)
+			data, err := io.ReadAll(r)
+			if tt.mismatched {
+				if !errors.Is(err, ErrChecksumMismatch) || !r.Mismatched() {
+					t.Fatalf("error = %v, mismatched = %v", err, r.Mismatched())
+				}
+				// The subsequent reads keep failing.
+				if _, err = r.Read(make([]byte, 1)); !errors.Is(err, ErrChecksumMismatch) {
+					t.Fatalf("read again: %v", err)
+				}
+			} else if err != nil || r.Mismatched() {
+				t.Fatalf("error = %v, mismatched = %v", err, r.Mismatched())
+			}
+			if string(data) != "hello" || r.Size() != 5 {
+				t.Fatalf("data = %q, size = %d", data, r.Size())
+			}
+			if digest := r.Digest(); digest.MD5 != helloMD5 || digest.SHA256 != helloSHA256 {
+				t.Fatalf("digest = %+v", digest)
+			}
+		})
+	}
+}
+
+func TestDigestReaderAbortsWrite(t *testing.T) {
+	for backend, fs := range testBackends(t) {
+		t.Run(backend, func(t *testing.T) {
+			r := NewDigestReader(io.NopCloser(strings.NewReader("hello")), &Digest{MD5: strings.Repeat("0", 32)})
+			if _, err := fs.CreateAndWrite(testContext(), "/dir/a", r); err == nil || !r.Mismatched() {
+				t.Fatalf("error = %v, mismatched = %v", err, r.Mismatched())
+			}
+			if exists, err := fs.IsExists(testContext(), "/dir/a"); err != nil || exists {
+				t.Fatalf("file exists = %v, %v", exists, err)
+			}
+		})
+	}
+}
+
+func TestComputeDigest(t *testing.T) {
+	digest, size, err := ComputeDigest(strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("compute digest: %v", err)
+	}
+	if size != 5 || digest.MD5 != helloMD5 || digest.SHA256 != helloSHA256 {
+		t.Fatalf("digest = %+v, size = %d", digest, size)
+	}
+}
@@ -0,0 +1,154 @@
+package fileio
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"sort"
+)
+
+// Fallback is a FileIO that reads from the primary and falls back to the secondary if
+// the file not exists in primary. It is used to serve reads during the migration from
+// secondary to primary. The new files are written to primary, and the removals are
+// applied to both so that the deleted files are not served from secondary again.
+type Fallback struct {
+	primary   FileIO
+	secondary FileIO
+}
+
+var _ FileIO = (*Fallback)(nil)
+
+// NewFallback creates a Fallback FileIO.
+func NewFallback(primary, secondary FileIO) *Fallback {
+	return &Fallback{primary: primary, secondary: secondary}
+}
+
+// Close does nothing, the primary and secondary are closed by their owner.
+func (f *Fallback) Close() error {
+	return nil
+}
+
+func (f *Fallback) MkdirAll(ctx context.Context, dirname string, perm os.FileMode) error {
+	return f.primary.MkdirAll(ctx, dirname, perm)
+}
+
+func (f *Fallback) IsExists(ctx context.Context, name string) (bool, error) {
+	exists, err := f.primary.IsExists(ctx, name)
+	if err != nil || exists {
+		return exists, err
+	}
+	return f.secondary.IsExists(ctx, name)
+}
+
+func (f *Fallback) Stat(ctx context.Context, name string) (*FileInfo, error) {
+	info, err := f.primary.Stat(ctx, name)
+	if err != nil && os.IsNotExist(err) {
+		return f.secondary.Stat(ctx, name)
+	}
+	return info, err
+}
+
+// List merges the entries in primary and secondary, the entry in primary wins if both exist.
+func (f *Fallback) List(ctx context.Context, dir string) ([]*FileInfo, error) {
+	infos, err := f.primary.List(ctx, dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	primaryErr := err
+	others, err := f.secondary.List(ctx, dir)
+	if err != nil {
+		if os.IsNotExist(err) && primaryErr == nil {
+			return infos, nil
+		}
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(infos))
+	for _, info := range infos {
+		seen[path.Base(info.Name)] = struct{}{}
+	}
+	for _, info := range others {
+		if _, ok := seen[path.Base(info.Name)]; !ok {
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return path.Base(infos[i].Name) < path.Base(infos[j].Name)
+	})
+	return infos, nil
+}
+
+func (f *Fallback) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	return walk(ctx, f, root, fn)
+}
+
+// CreateAndWrite writes the file to primary. Returns an error that satisfies os.IsExist
+// if the file exists in secondary, because the file in secondary will be migrated later.
+func (f *Fallback) CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (string, error) {
+	exists, err := f.secondary.IsExists(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return "", &os.PathError{Op: "create", Path: name, Err: os.ErrExist}
+	}
+	return f.primary.CreateAndWrite(ctx, name, reader)
+}
+
+func (f *Fallback) OpenForRead(ctx context.Context, name string) (io.ReadCloser, error) {
+	reader, err := f.primary.OpenForRead(ctx, name)
+	if err != nil && os.IsNotExist(err) {
+		return f.secondary.OpenForRead(ctx, name)
+	}
+	return reader, err
+}
+
+func (f *Fallback) OpenRange(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	reader, err := f.primary.OpenRange(ctx, name, offset, length)
+	if err != nil && os.IsNotExist(err) {
+		return f.secondary.OpenRange(ctx, name, offset, length)
+	}
+	return reader, err
+}
+
+// Remove removes the file from both. Returns an error that satisfies os.IsNotExist only if not exists in both.
+func (f *Fallback) Remove(ctx context.Context, name string) error {
+	err1 := f.primary.Remove(ctx, name)
+	if err1 != nil && !os.IsNotExist(err1) {
+		return err1
+	}
+	err2 := f.secondary.Remove(ctx, name)
+	if err2 != nil && !os.IsNotExist(err2) {
+		return err2
+	}
+	if err1 != nil && err2 != nil {
+		return err1
+	}
+	return nil
+}
+
+func (f *Fallback) RemoveAll(ctx context.Context, name string) error {
+	if err := f.primary.RemoveAll(ctx, name); err != nil {
+		return err
+	}
+	return f.secondary.RemoveAll(ctx, name)
+}
+
+// Rename renames the file in primary, or in secondary if not exists in primary.
+func (f *Fallback) Rename(ctx context.Context, oldName string, newName string) error {
+	err := f.primary.Rename(ctx, oldName, newName)
+	if err != nil && os.IsNotExist(err) {
+		return f.secondary.Rename(ctx, oldName, newName)
+	}
+	return err
+}
+
+// RenameNoReplace is same as Rename but never overwrites newName.
+func (f *Fallback) RenameNoReplace(ctx context.Context, oldName string, newName string) error {
+	err := RenameNoReplace(ctx, f.primary, oldName, newName)
+	if err != nil && os.IsNotExist(err) {
+		return RenameNoReplace(ctx, f.secondary, oldName, newName)
+	}
+	return err
+}
@@ -0,0 +1,211 @@
+package fileio
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newTestFallback returns a Fallback that primary has /dir/a and /dir/both, and secondary
+// has /dir/b and /dir/both.
+func newTestFallback(t *testing.T) (*Fallback, *Memory, *Memory) {
+	t.Helper()
+	primary, secondary := newTestMemory(t, 0), newTestMemory(t, 0)
+	writeString(t, primary, "/dir/a", "primary a")
+	writeString(t, primary, "/dir/both", "primary both")
+	writeString(t, secondary, "/dir/b", "secondary b")
+	writeString(t, secondary, "/dir/both", "secondary both")
+	return NewFallback(primary, secondary), primary, secondary
+}
+
+func TestFallbackRead(t *testing.T) {
+	f, _, _ := newTestFallback(t)
+	ctx := testContext()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "/dir/a", want: "primary a"},
+		{name: "/dir/b", want: "secondary b"},
+		{name: "/dir/both", want: "primary both"},
+		{name: "/dir/none"},
+	}
+	for _, tt := range tests {
+		exists, err := f.IsExists(ctx, tt.name)
+		if err != nil || exists != (tt.want != "") {
+			t.Fatalf("is exists %s = %v, %v", tt.name, exists, err)
+		}
+		if tt.want == "" {
+			if _, err = f.OpenForRead(ctx, tt.name); !os.IsNotExist(err) {
+				t.Fatalf("open %s: %v", tt.name, err)
+			}
+			if _, err = f.Stat(ctx, tt.name); !os.IsNotExist(err) {
+				t.Fatalf("stat %s: %v", tt.name, err)
+			}
+			continue
+		}
+		if got := readString(t, f, tt.name); got != tt.want {
+			t.Fatalf("read %s = %q, want %q", tt.name, got, tt.want)
+		}
+		info, err := f.Stat(ctx, tt.name)
+		if err != nil || info.Size != int64(len(tt.want)) {
+			t.Fatalf("stat %s = %v, %v", tt.name, info, err)
+		}
+		reader, err := f.OpenRange(ctx, tt.name, 2, 3)
+		if err != nil {
+			t.Fatalf("open range %s: %v", tt.name, err)
+		}
+		data, _ := io.ReadAll(reader)
+		_ = reader.Close()
+		if string(data) != tt.want[2:5] {
+			t.Fatalf("range %s = %q, want %q", tt.name, data, tt.want[2:5])
+		}
+	}
+
+	files, err := f.List(ctx, "/dir")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var names []string
+	for _, info := range files {
+		names = append(names, info.Name)
+		// The entry in primary wins.
+		if info.Name == "/dir/both" && info.Size != int64(len("primary both")) {
+			t.Fatal("list returns the entry in secondary")
+		}
+	}
+	if got := strings.Join(names, ","); got != "/dir/a,/dir/b,/dir/both" {
+		t.Fatalf("list = %s", got)
+	}
+}
+
+// TestFallbackReadS3 checks the not found error of S3 as the primary makes the read fall back.
+func TestFallbackReadS3(t *testing.T) {
+	svc := newFakeS3()
+	svc.objects["/dir/a"] = []byte("primary a")
+	secondary := newTestMemory(t, 0)
+	writeString(t, secondary, "/dir/b", "secondary b")
+	f := NewFallback(newTestS3Client(svc, 1), secondary)
+	ctx := testContext()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "/dir/a", want: "primary a"},
+		{name: "/dir/b", want: "secondary b"},
+		{name: "/dir/none"},
+	}
+	for _, tt := range tests {
+		if tt.want == "" {
+			if _, err := f.OpenForRead(ctx, tt.name); !os.IsNotExist(err) {
+				t.Fatalf("open %s: %v", tt.name, err)
+			}
+			if _, err := f.OpenRange(ctx, tt.name, 2, 3); !os.IsNotExist(err) {
+				t.Fatalf("open range %s: %v", tt.name, err)
+			}
+			continue
+		}
+		if got := readString(t, f, tt.name); got != tt.want {
+			t.Fatalf("read %s = %q, want %q", tt.name, got, tt.want)
+		}
+		reader, err := f.OpenRange(ctx, tt.name, 2, 3)
+		if err != nil {
+			t.Fatalf("open range %s: %v", tt.name, err)
+		}
+		data, _ := io.ReadAll(reader)
+		_ = reader.Close()
+		if string(data) != tt.want[2:5] {
+			t.Fatalf("range %s = %q, want %q", tt.name, data, tt.want[2:5])
+		}
+	}
+}
+
+func TestFallbackList(t *testing.T) {
+	ctx := testContext()
+	primary, secondary := newTestMemory(t, 0), newTestMemory(t, 0)
+	f := NewFallback(primary, secondary)
+	if err := secondary.MkdirAll(ctx, "/only/secondary", 0777); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := primary.MkdirAll(ctx, "/only/primary", 0777); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	tests := []struct {
+		dir   string
+		count int
+	}{
+		{dir: "/only", count: 2},
+		{dir: "/only/secondary", count: 0},
+		{dir: "/only/primary", count: 0},
+	}
+	for _, tt := range tests {
+		files, err := f.List(ctx, tt.dir)
+		if err != nil || len(files) != tt.count {
+			t.Fatalf("list %s = %d, %v", tt.dir, len(files), err)
+		}
+	}
+	if _, err := f.List(ctx, "/none"); !os.IsNotExist(err) {
+		t.Fatalf("list not exists: %v", err)
+	}
+}
+
+func TestFallbackWrite(t *testing.T) {
+	ctx := testContext()
+	f, primary, secondary := newTestFallback(t)
+
+	// The new file is written to primary.
+	writeString(t, f, "/dir/c", "c")
+	if exists, _ := primary.IsExists(ctx, "/dir/c"); !exists {
+		t.Fatal("the new file is not in primary")
+	}
+	// The file in secondary can not be created again.
+	if _, err := f.CreateAndWrite(ctx, "/dir/b", io.NopCloser(strings.NewReader("b"))); !os.IsExist(err) {
+		t.Fatalf("create exists in secondary: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		err  func(error) bool
+	}{
+		{name: "/dir/a"},
+		{name: "/dir/b"},
+		{name: "/dir/both"},
+		{name: "/dir/none", err: os.IsNotExist},
+	}
+	for _, tt := range tests {
+		err := f.Remove(ctx, tt.name)
+		if tt.err != nil {
+			if !tt.err(err) {
+				t.Fatalf("remove %s: %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("remove %s: %v", tt.name, err)
+		}
+		// The removed file is not served from secondary again.
+		for _, fs := range []FileIO{primary, secondary, f} {
+			if exists, _ := fs.IsExists(ctx, tt.name); exists {
+				t.Fatalf("%s exists after removed", tt.name)
+			}
+		}
+	}
+
+	// The rename is applied where the file is.
+	writeString(t, secondary, "/dir/d", "d")
+	if err := f.Rename(ctx, "/dir/d", "/dir/e"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if got := readString(t, secondary, "/dir/e"); got != "d" {
+		t.Fatalf("renamed = %q", got)
+	}
+
+	if err := f.RemoveAll(ctx, "/dir"); err != nil {
+		t.Fatalf("remove all: %v", err)
+	}
+	if exists, _ := f.IsExists(ctx, "/dir"); exists {
+		t.Fatal("dir exists after removed")
+	}
+}
@@ -2,10 +2,116 @@ package fileio
 
 import (
 	"context"
+	"errors"
 	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataWorkbench/glog"
+)
+
+var (
+	// ErrStorageFull is returned when the data to be written exceeds the capacity of storage.
+	ErrStorageFull = errors.New("storage is full")
+
+	// ErrInvalidRange is returned when the offset of range read is beyond the file size.
+	ErrInvalidRange = errors.New("invalid range")
 )
 
+// stagingMarker is the marker in name of the staging file that data is being written into.
+const stagingMarker = ".staging-"
+
+// stagingName returns a unique staging file name in the same directory of name.
+func stagingName(name string) string {
+	return name + stagingMarker + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// IsStagingFile reports whether the file is a staging file created by CreateAndWrite.
+func IsStagingFile(name string) bool {
+	return strings.Contains(path.Base(name), stagingMarker)
+}
+
+// SweepStagingFiles removes the staging files under root that not modified within expire.
+// They are left behind by the process that crashed when writing. Returns the number of removed files.
+func SweepStagingFiles(ctx context.Context, fs FileIO, root string, expire time.Duration) (removed int, err error) {
+	lg := glog.FromContext(ctx)
+	deadline := time.Now().Add(-expire)
+	err = fs.Walk(ctx, root, func(name string, info *FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir || !IsStagingFile(name) || info.ModTime.After(deadline) {
+			return nil
+		}
+		lg.Info().Msg("remove stale staging file").String("name", name).Time("modTime", info.ModTime, time.RFC3339).Fire()
+		if err = fs.Remove(ctx, name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return
+}
+
+// Pinger is implemented by the FileIO that has a cheaper way than stat on root to check
+// whether the storage is reachable.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks whether the storage of fs is reachable. It stats the root if fs is not a Pinger.
+func Ping(ctx context.Context, fs FileIO) error {
+	if pinger, ok := fs.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	_, err := fs.Stat(ctx, "/")
+	return err
+}
+
+type ctxUserKey struct{}
+
+// ContextWithUser store the user in context.Value. The storage that supports
+// impersonation, such as HDFS, will act as the user for operations with the context.
+func ContextWithUser(ctx context.Context, user string) context.Context {
+	if user == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxUserKey{}, user)
+}
+
+// UserFromContext get the user from context.Value. Returns empty if not set.
+func UserFromContext(ctx context.Context) (user string) {
+	user, _ = ctx.Value(ctxUserKey{}).(string)
+	return
+}
+
+// FileInfo describes a file or directory in storage.
+type FileInfo struct {
+	// Name is the full path of the file.
+	Name string
+	// Size is the length in bytes, always 0 for directory.
+	Size int64
+	// ModTime is the last modification time.
+	ModTime time.Time
+	// IsDir reports whether the file is a directory.
+	IsDir bool
+	// ETag is the checksum of file data as hex. Empty if the backend does not known it.
+	ETag string
+	// Extra holds the backend-specific attributes, such as owner in HDFS or storage class in S3.
+	Extra map[string]string
+}
+
+// WalkFunc is the type of the function called by Walk to visit each file or directory.
+// The semantics is same as filepath.WalkFunc, return filepath.SkipDir to skip a directory.
+type WalkFunc func(name string, info *FileInfo, err error) error
+
 type FileIO interface {
 	// Close for close the connect.
 	Close() error
@@ -16,13 +122,31 @@ type FileIO interface {
 	// IsExists for check a file whether exists.
 	IsExists(ctx context.Context, name string) (bool, error)
 
+	// Stat returns the FileInfo of the file or directory. Returns an error
+	// that satisfies os.IsNotExist if the file does not exist.
+	Stat(ctx context.Context, name string) (*FileInfo, error)
+
+	// List returns the files and directories directly under the dir, sorted by name.
+	List(ctx context.Context, dir string) ([]*FileInfo, error)
+
+	// Walk walks the file tree rooted at root in lexical order, calling fn for
+	// each file or directory in the tree, including root.
+	Walk(ctx context.Context, root string, fn WalkFunc) error
+
 	// CreateAndWrite for create a file and writing data into.
+	// The file is published atomically only after all data written successfully,
+	// so readers never see a partial file. Nothing is published if the reader returns error.
 	// Return eTag(md5 as hex), error
 	CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (string, error)
 
 	// OpenForRead for open a file to read data.
 	OpenForRead(ctx context.Context, name string) (io.ReadCloser, error)
 
+	// OpenRange for open a file to read the data in range [offset, offset+length).
+	// The length <= 0 means read to the end of file. Returns ErrInvalidRange if
+	// the offset is greater than the file size.
+	OpenRange(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error)
+
 	// Remove for delete a file.
 	Remove(ctx context.Context, name string) error
 
@@ -32,5 +156,82 @@ type FileIO interface {
 	RemoveAll(ctx context.Context, name string) error
 
 	// Rename for rename a file name to `newName` from `oldName`.
+	// Same as HDFS, `newName` is overwritten if it is a file. Use RenameNoReplace to keep it.
 	Rename(ctx context.Context, oldName string, newName string) error
 }
+
+// NoReplaceRenamer is implemented by the FileIO that can rename without overwriting
+// the destination atomically.
+type NoReplaceRenamer interface {
+	RenameNoReplace(ctx context.Context, oldName string, newName string) error
+}
+
+// RenameNoReplace renames oldName to newName, returns an error that satisfies os.IsExist if
+// newName exists. If fs is not a NoReplaceRenamer, newName is checked before rename, so a
+// concurrent writer to newName may still be overwritten.
+func RenameNoReplace(ctx context.Context, fs FileIO, oldName string, newName string) error {
+	if renamer, ok := fs.(NoReplaceRenamer); ok {
+		return renamer.RenameNoReplace(ctx, oldName, newName)
+	}
+	if _, err := fs.Stat(ctx, newName); err == nil {
+		return &os.PathError{Op: "rename", Path: newName, Err: os.ErrExist}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	return fs.Rename(ctx, oldName, newName)
+}
+
+// rangeReadCloser is used to limit the length of read data in OpenRange.
+type rangeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// limitRange returns a io.ReadCloser that reads at most length bytes from rc.
+// The length <= 0 means no limit.
+func limitRange(rc io.ReadCloser, length int64) io.ReadCloser {
+	if length <= 0 {
+		return rc
+	}
+	return &rangeReadCloser{Reader: io.LimitReader(rc, length), Closer: rc}
+}
+
+// walk is the generic implementation of FileIO.Walk that based on FileIO.Stat and FileIO.List.
+func walk(ctx context.Context, fs FileIO, root string, fn WalkFunc) error {
+	info, err := fs.Stat(ctx, root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDir(ctx, fs, info, fn)
+	}
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func walkDir(ctx context.Context, fs FileIO, info *FileInfo, fn WalkFunc) error {
+	if !info.IsDir {
+		return fn(info.Name, info, nil)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	infos, err := fs.List(ctx, info.Name)
+	err1 := fn(info.Name, info, err)
+	// Same as filepath.Walk, If err != nil, walk can't walk into this directory.
+	if err != nil || err1 != nil {
+		return err1
+	}
+
+	for _, child := range infos {
+		err = walkDir(ctx, fs, child, fn)
+		if err != nil {
+			if !child.IsDir || err != filepath.SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
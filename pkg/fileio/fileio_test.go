@@ -0,0 +1,379 @@
+package fileio
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testBackends returns the backends that can be tested without external service,
+// each one has an empty directory `/dir`.
+func testBackends(t *testing.T) map[string]FileIO {
+	t.Helper()
+	local, err := NewLocal(testContext(), t.TempDir())
+	if err != nil {
+		t.Fatalf("new local: %v", err)
+	}
+	if err = local.MkdirAll(testContext(), "/dir", 0777); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	return map[string]FileIO{
+		"local":  local,
+		"memory": newTestMemory(t, 0),
+	}
+}
+
+func TestRenameNoReplace(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		dirs    []string
+		oldName string
+		newName string
+		check   func(error) bool
+		want    map[string]string
+	}{
+		{
+			name:    "file",
+			files:   map[string]string{"/dir/a": "a"},
+			oldName: "/dir/a",
+			newName: "/dir/b",
+			want:    map[string]string{"/dir/b": "a"},
+		},
+		{
+			name:    "file to existing file",
+			files:   map[string]string{"/dir/a": "a", "/dir/b": "b"},
+			oldName: "/dir/a",
+			newName: "/dir/b",
+			check:   os.IsExist,
+			want:    map[string]string{"/dir/a": "a", "/dir/b": "b"},
+		},
+		{
+			name:    "file to existing directory",
+			files:   map[string]string{"/dir/a": "a"},
+			dirs:    []string{"/dir/b"},
+			oldName: "/dir/a",
+			newName: "/dir/b",
+			check:   os.IsExist,
+			want:    map[string]string{"/dir/a": "a"},
+		},
+		{
+			name:    "directory",
+			files:   map[string]string{"/dir/a/x": "x"},
+			oldName: "/dir/a",
+			newName: "/dir/b",
+			want:    map[string]string{"/dir/b/x": "x"},
+		},
+		{
+			name:    "directory to existing empty directory",
+			files:   map[string]string{"/dir/a/x": "x"},
+			dirs:    []string{"/dir/b"},
+			oldName: "/dir/a",
+			newName: "/dir/b",
+			check:   os.IsExist,
+			want:    map[string]string{"/dir/a/x": "x"},
+		},
+		{
+			name:    "directory to existing directory",
+			files:   map[string]string{"/dir/a/x": "x", "/dir/b/y": "y"},
+			oldName: "/dir/a",
+			newName: "/dir/b",
+			check:   os.IsExist,
+			want:    map[string]string{"/dir/a/x": "x", "/dir/b/y": "y"},
+		},
+		{
+			name:    "not exists",
+			oldName: "/dir/a",
+			newName: "/dir/b",
+			check:   os.IsNotExist,
+		},
+	}
+	for _, tt := range tests {
+		for backend, fs := range testBackends(t) {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				ctx := testContext()
+				for _, dir := range tt.dirs {
+					if err := fs.MkdirAll(ctx, dir, 0777); err != nil {
+						t.Fatalf("mkdir: %v", err)
+					}
+				}
+				for name, data := range tt.files {
+					if err := fs.MkdirAll(ctx, name[:strings.LastIndex(name, "/")], 0777); err != nil {
+						t.Fatalf("mkdir: %v", err)
+					}
+					writeString(t, fs, name, data)
+				}
+
+				err := RenameNoReplace(ctx, fs, tt.oldName, tt.newName)
+				if tt.check != nil {
+					if err == nil || !tt.check(err) {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				} else if err != nil {
+					t.Fatalf("rename: %v", err)
+				}
+				for name, data := range tt.want {
+					if got := readString(t, fs, name); got != data {
+						t.Fatalf("%s = %q, want %q", name, got, data)
+					}
+				}
+				if tt.check == nil {
+					if exists, err := fs.IsExists(ctx, tt.oldName); err != nil || exists {
+						t.Fatalf("old name exists = %v, %v", exists, err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRenameReplace(t *testing.T) {
+	for backend, fs := range testBackends(t) {
+		t.Run(backend, func(t *testing.T) {
+			ctx := testContext()
+			writeString(t, fs, "/dir/a", "a")
+			writeString(t, fs, "/dir/b", "b")
+			if err := fs.Rename(ctx, "/dir/a", "/dir/b"); err != nil {
+				t.Fatalf("rename: %v", err)
+			}
+			if got := readString(t, fs, "/dir/b"); got != "a" {
+				t.Fatalf("/dir/b = %q, want %q", got, "a")
+			}
+			if exists, err := fs.IsExists(ctx, "/dir/a"); err != nil || exists {
+				t.Fatalf("old name exists = %v, %v", exists, err)
+			}
+		})
+	}
+}
+
+// failedReader returns err after data read.
+type failedReader struct {
+	data io.Reader
+	err  error
+}
+
+func (r *failedReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func (r *failedReader) Close() error {
+	return nil
+}
+
+func TestStat(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int64
+		isDir bool
+	}{
+		{name: "/dir", isDir: true},
+		{name: "/dir/a", size: 5},
+		{name: "/dir/sub", isDir: true},
+	}
+	for backend, fs := range testBackends(t) {
+		t.Run(backend, func(t *testing.T) {
+			ctx := testContext()
+			before := time.Now().Add(-time.Second)
+			if err := fs.MkdirAll(ctx, "/dir/sub", 0777); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+			writeString(t, fs, "/dir/a", "hello")
+			for _, tt := range tests {
+				info, err := fs.Stat(ctx, tt.name)
+				if err != nil {
+					t.Fatalf("stat %s: %v", tt.name, err)
+				}
+				if info.Name != tt.name || info.Size != tt.size || info.IsDir != tt.isDir || info.ModTime.Before(before) {
+					t.Fatalf("stat %s = %+v", tt.name, info)
+				}
+			}
+			if _, err := fs.Stat(ctx, "/dir/none"); !os.IsNotExist(err) {
+				t.Fatalf("stat not exists: %v", err)
+			}
+		})
+	}
+}
+
+func TestListAndWalk(t *testing.T) {
+	tests := []struct {
+		name string
+		skip string // The directory to skip in walk.
+		want string
+	}{
+		{name: "all", want: "/dir,/dir/a,/dir/b,/dir/b/c,/dir/b/d,/dir/e"},
+		{name: "skip directory", skip: "/dir/b", want: "/dir,/dir/a,/dir/b,/dir/e"},
+		{name: "skip root", skip: "/dir", want: "/dir"},
+	}
+	for backend, fs := range testBackends(t) {
+		ctx := testContext()
+		if err := fs.MkdirAll(ctx, "/dir/b/d", 0777); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		writeString(t, fs, "/dir/e", "e")
+		writeString(t, fs, "/dir/b/c", "c")
+		writeString(t, fs, "/dir/a", "a")
+
+		files, err := fs.List(ctx, "/dir")
+		if err != nil {
+			t.Fatalf("%s: list: %v", backend, err)
+		}
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name)
+		}
+		if got := strings.Join(names, ","); got != "/dir/a,/dir/b,/dir/e" {
+			t.Fatalf("%s: list = %s", backend, got)
+		}
+		if _, err = fs.List(ctx, "/none"); !os.IsNotExist(err) {
+			t.Fatalf("%s: list not exists: %v", backend, err)
+		}
+
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				var names []string
+				err := fs.Walk(ctx, "/dir", func(name string, info *FileInfo, err error) error {
+					if err != nil {
+						return err
+					}
+					names = append(names, name)
+					if info.IsDir && name == tt.skip {
+						return filepath.SkipDir
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("walk: %v", err)
+				}
+				if got := strings.Join(names, ","); got != tt.want {
+					t.Fatalf("walk = %s, want %s", got, tt.want)
+				}
+			})
+		}
+
+		// The error of root is passed to fn.
+		err = fs.Walk(ctx, "/none", func(name string, info *FileInfo, err error) error {
+			return err
+		})
+		if !os.IsNotExist(err) {
+			t.Fatalf("%s: walk not exists: %v", backend, err)
+		}
+	}
+}
+
+func TestOpenRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   string
+		err    error
+	}{
+		{name: "all", want: "0123456789"},
+		{name: "middle", offset: 2, length: 3, want: "234"},
+		{name: "to end", offset: 7, length: -1, want: "789"},
+		{name: "beyond length", offset: 8, length: 10, want: "89"},
+		{name: "at end", offset: 10, length: 1, want: ""},
+		{name: "beyond end", offset: 11, length: 1, err: ErrInvalidRange},
+		{name: "negative", offset: -1, length: 1, err: ErrInvalidRange},
+	}
+	backends := testBackends(t)
+	backends["s3"] = newTestS3Client(newFakeS3(), 1)
+	for backend, fs := range backends {
+		writeString(t, fs, "/dir/a", "0123456789")
+		for _, tt := range tests {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				reader, err := fs.OpenRange(testContext(), "/dir/a", tt.offset, tt.length)
+				if tt.err != nil {
+					if !errors.Is(err, tt.err) {
+						t.Fatalf("error = %v, want %v", err, tt.err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("open range: %v", err)
+				}
+				defer func() { _ = reader.Close() }()
+				data, err := io.ReadAll(reader)
+				if err != nil {
+					t.Fatalf("read: %v", err)
+				}
+				if string(data) != tt.want {
+					t.Fatalf("data = %q, want %q", data, tt.want)
+				}
+			})
+		}
+		if _, err := fs.OpenRange(testContext(), "/dir/none", 0, 1); !os.IsNotExist(err) {
+			t.Fatalf("%s: open range not exists: %v", backend, err)
+		}
+	}
+}
+
+func TestCreateAndWriteAborted(t *testing.T) {
+	errAborted := errors.New("aborted")
+	for backend, fs := range testBackends(t) {
+		t.Run(backend, func(t *testing.T) {
+			ctx := testContext()
+			reader := &failedReader{data: strings.NewReader(strings.Repeat("x", 10000)), err: errAborted}
+			if _, err := fs.CreateAndWrite(ctx, "/dir/a", reader); !errors.Is(err, errAborted) {
+				t.Fatalf("error = %v, want %v", err, errAborted)
+			}
+			// Neither the file nor the staging file is left.
+			files, err := fs.List(ctx, "/dir")
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if len(files) != 0 {
+				t.Fatalf("files left: %s", files[0].Name)
+			}
+		})
+	}
+}
+
+func TestSweepStagingFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		expire  time.Duration
+		removed int
+	}{
+		{name: "not expired", expire: time.Hour, removed: 0},
+		{name: "expired", expire: 0, removed: 2},
+	}
+	for _, tt := range tests {
+		for backend, fs := range testBackends(t) {
+			t.Run(backend+"/"+tt.name, func(t *testing.T) {
+				ctx := testContext()
+				if err := fs.MkdirAll(ctx, "/dir/sub", 0777); err != nil {
+					t.Fatalf("mkdir: %v", err)
+				}
+				writeString(t, fs, "/dir/a", "a")
+				writeString(t, fs, stagingName("/dir/a"), "a")
+				writeString(t, fs, stagingName("/dir/sub/b"), "b")
+				time.Sleep(time.Millisecond)
+
+				removed, err := SweepStagingFiles(ctx, fs, "/dir", tt.expire)
+				if err != nil {
+					t.Fatalf("sweep: %v", err)
+				}
+				if removed != tt.removed {
+					t.Fatalf("removed = %d, want %d", removed, tt.removed)
+				}
+				if readString(t, fs, "/dir/a") != "a" {
+					t.Fatal("the published file is removed")
+				}
+			})
+		}
+	}
+
+	// The root not exists is not an error.
+	if _, err := SweepStagingFiles(testContext(), newTestMemory(t, 0), "/none", 0); err != nil {
+		t.Fatalf("sweep not exists: %v", err)
+	}
+}
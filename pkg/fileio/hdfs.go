@@ -4,46 +4,65 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/hex"
+	"errors"
 	"io"
 	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/DataWorkbench/common/qerror"
 	"github.com/DataWorkbench/glog"
 	"github.com/colinmarc/hdfs/v2"
 	"github.com/colinmarc/hdfs/v2/hadoopconf"
+	"github.com/opentracing/opentracing-go"
 )
 
 var _ FileIO = (*HDFS)(nil)
 
+// defaultHadoopUser is the user to access HDFS if not specified and kerberos disabled.
+const defaultHadoopUser = "root"
+
+// HDFS implements the FileIO by hadoop distributed filesystem. The operations acts as
+// the user in context that set by ContextWithUser, or the default user if not set.
 type HDFS struct {
-	client *hdfs.Client
+	pool     *hdfsPool
+	user     string // The default user.
+	namenode string // The addresses of namenode joined by comma.
+	kerberos *KerberosConfig
+	done     chan struct{}
 }
 
-func NewHadoopClientFromEnv(ctx context.Context, username string) (*HDFS, error) {
+// NewHadoopClientFromEnv create a HDFS client by the hadoop config in environment.
+// The username is the default user to access HDFS, use root if empty,
+// or the kerberos principal if kerberos enabled. The krbCfg is required if kerberos is
+// enabled in hadoop config. The poolSize is the max number of clients for different users.
+func NewHadoopClientFromEnv(ctx context.Context, username string, krbCfg *KerberosConfig, poolSize int) (*HDFS, error) {
 	conf, err := hadoopconf.LoadFromEnvironment()
 	if err != nil || conf == nil {
 		return nil, qerror.HadoopClientCreateFailed
 	}
-	return newHadoopClientFromConf(ctx, conf, username)
+	return newHadoopClientFromConf(ctx, conf, username, krbCfg, poolSize)
 }
 
-func NewHadoopClientFromConfMap(ctx context.Context, confMap map[string]string, username string) (*HDFS, error) {
+func NewHadoopClientFromConfMap(ctx context.Context, confMap map[string]string, username string, krbCfg *KerberosConfig, poolSize int) (*HDFS, error) {
 	conf := hadoopconf.HadoopConf{}
 	for k, v := range confMap {
 		conf[k] = v
 	}
-	return newHadoopClientFromConf(ctx, conf, username)
+	return newHadoopClientFromConf(ctx, conf, username, krbCfg, poolSize)
 }
 
-func NewHadoopClientFromConfFile(ctx context.Context, confPath string, username string) (*HDFS, error) {
+func NewHadoopClientFromConfFile(ctx context.Context, confPath string, username string, krbCfg *KerberosConfig, poolSize int) (*HDFS, error) {
 	conf, err := hadoopconf.Load(confPath)
 	if err != nil || conf == nil {
 		return nil, qerror.HadoopClientCreateFailed
 	}
-	return newHadoopClientFromConf(ctx, conf, username)
+	return newHadoopClientFromConf(ctx, conf, username, krbCfg, poolSize)
 }
 
-func newHadoopClientFromConf(ctx context.Context, conf hadoopconf.HadoopConf, username string) (*HDFS, error) {
+func newHadoopClientFromConf(ctx context.Context, conf hadoopconf.HadoopConf, username string, krbCfg *KerberosConfig, poolSize int) (*HDFS, error) {
 	lg := glog.FromContext(ctx)
 
 	lg.Info().Msg("hdfs creating new client").Fire()
@@ -57,27 +76,134 @@ func newHadoopClientFromConf(ctx context.Context, conf hadoopconf.HadoopConf, us
 	if options.Addresses == nil {
 		return nil, qerror.HadoopClientCreateFailed
 	}
+
+	if username == "" && options.KerberosClient == nil {
+		username = defaultHadoopUser
+	}
+
+	hadoopClient := &HDFS{
+		user:     username,
+		namenode: strings.Join(options.Addresses, ","),
+		done:     make(chan struct{}),
+	}
 	if options.KerberosClient != nil {
-		//TODO future load kerberos file
-		options.User = username
-	} else {
-		options.User = username
+		krbClient, err := newKerberosClient(krbCfg)
+		if err != nil {
+			lg.Error().Error("hdfs login kerberos failed", err).Fire()
+			return nil, err
+		}
+		lg.Info().Msg("hdfs login kerberos success").
+			String("principal", krbClient.Credentials.CName().PrincipalNameString()).
+			String("realm", krbClient.Credentials.Realm()).Fire()
+
+		options.KerberosClient = krbClient
+		if krbCfg.ServicePrincipalName != "" {
+			options.KerberosServicePrincipleName = krbCfg.ServicePrincipalName
+		}
+		hadoopClient.kerberos = krbCfg
 	}
-	client, err := hdfs.NewClient(options)
-	if err != nil || client == nil {
+	hadoopClient.pool = newHdfsPool(options, poolSize)
+
+	// Create the client of default user to check the config is valid.
+	// The user will be determined from the kerberos credentials if it is empty.
+	client, err := hadoopClient.pool.acquire(username)
+	if err != nil {
 		lg.Error().Error("hdfs create client failed", err).Fire()
 		return nil, err
 	}
-	hadoopClient := HDFS{client: client}
-	return &hadoopClient, nil
+	hadoopClient.pool.release(client)
+
+	if hadoopClient.kerberos != nil {
+		go hadoopClient.renewKerberos(ctx)
+	}
+	return hadoopClient, nil
+}
+
+// renewKerberos renew the kerberos ticket periodically until the client closed.
+func (hd *HDFS) renewKerberos(ctx context.Context) {
+	lg := glog.FromContext(ctx)
+
+	ticker := time.NewTicker(hd.kerberos.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-hd.done:
+			return
+		case <-ticker.C:
+		}
+
+		if err := hd.renew(); err != nil {
+			lg.Error().Msg("hdfs: renew kerberos ticket failed").Error("error", err).Fire()
+			continue
+		}
+		lg.Info().Msg("hdfs: renew kerberos ticket success").Fire()
+	}
+}
+
+func (hd *HDFS) renew() error {
+	options := hd.pool.getOptions()
+
+	// The client that login by keytab can get a new TGT by self.
+	if hd.kerberos.useKeytab() {
+		return options.KerberosClient.Login()
+	}
+
+	// The client that login by ccache can not renew the TGT, so reload the ccache
+	// and recreate the hdfs clients. The requests in progress still use the old one.
+	krbClient, err := newKerberosClient(hd.kerberos)
+	if err != nil {
+		return err
+	}
+	options.KerberosClient = krbClient
+	hd.pool.reset(options)
+	return nil
+}
+
+// acquire returns the hdfs client of the user in context.
+// Must call hd.pool.release after the client no longer used.
+func (hd *HDFS) acquire(ctx context.Context) (*hdfsClient, error) {
+	username := UserFromContext(ctx)
+	if username == "" {
+		username = hd.user
+	}
+	client, err := hd.pool.acquire(username)
+	if err != nil {
+		lg := glog.FromContext(ctx)
+		lg.Error().Msg("hdfs: create client failed").String("user", username).Error("error", err).Fire()
+		return nil, err
+	}
+	return client, nil
+}
+
+// SpanTags returns the tags to set on the spans of Traced.
+func (hd *HDFS) SpanTags() opentracing.Tags {
+	return opentracing.Tags{"hdfs.namenode": hd.namenode}
+}
+
+// Ping stats the root as the default user to check the namenode is reachable.
+func (hd *HDFS) Ping(ctx context.Context) error {
+	client, err := hd.pool.acquire(hd.user)
+	if err != nil {
+		return err
+	}
+	defer hd.pool.release(client)
+
+	_, err = client.Stat("/")
+	return err
 }
 
 func (hd *HDFS) Close() error {
 	if hd == nil {
 		return nil
 	}
-	if hd.client != nil {
-		return hd.client.Close()
+	select {
+	case <-hd.done:
+	default:
+		close(hd.done)
+	}
+	if hd.pool != nil {
+		return hd.pool.close()
 	}
 	return nil
 }
@@ -87,7 +213,13 @@ func (hd *HDFS) MkdirAll(ctx context.Context, dirname string, perm os.FileMode)
 	lg.Debug().Msg("hdfs: create directory recursively").
 		String("dirname", dirname).
 		Uint32("perm", uint32(perm)).Fire()
-	err := hd.client.MkdirAll(dirname, perm)
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer hd.pool.release(client)
+
+	err = client.MkdirAll(dirname, perm)
 	if err != nil {
 		lg.Error().Msg("hdfs: create create directory recursively failed").Error("error", err).Fire()
 		return err
@@ -98,25 +230,132 @@ func (hd *HDFS) MkdirAll(ctx context.Context, dirname string, perm os.FileMode)
 func (hd *HDFS) IsExists(ctx context.Context, name string) (bool, error) {
 	lg := glog.FromContext(ctx)
 	lg.Debug().Msg("hdfs: check file is exists").String("name", name).Fire()
-	_, err := hd.client.Stat(name)
+	client, err := hd.acquire(ctx)
 	if err != nil {
-		lg.Warn().Msg("hdfs: stat file failed").Error("error", err).Fire()
-		if _, ok := err.(*os.PathError); ok {
-			err = nil
+		return false, err
+	}
+	defer hd.pool.release(client)
+
+	_, err = client.Stat(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
 		}
+		lg.Warn().Msg("hdfs: stat file failed").Error("error", err).Fire()
 		return false, err
 	}
 	return true, nil
 }
 
-func (hd *HDFS) CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (string, error) {
+func (hd *HDFS) Stat(ctx context.Context, name string) (*FileInfo, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("hdfs: stat file").String("name", name).Fire()
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer hd.pool.release(client)
+
+	info, err := client.Stat(name)
+	if err != nil {
+		lg.Warn().Msg("hdfs: stat file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	return hdfsFileInfo(name, info), nil
+}
+
+func hdfsFileInfo(name string, info os.FileInfo) *FileInfo {
+	fi := &FileInfo{
+		Name:    name,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+		Extra: map[string]string{
+			"permission": info.Mode().Perm().String(),
+		},
+	}
+	if hi, ok := info.(*hdfs.FileInfo); ok {
+		fi.Extra["owner"] = hi.Owner()
+		fi.Extra["group"] = hi.OwnerGroup()
+		fi.Extra["access_time"] = hi.AccessTime().Format(time.RFC3339)
+	}
+	return fi
+}
+
+func (hd *HDFS) List(ctx context.Context, dir string) ([]*FileInfo, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("hdfs: list directory").String("dir", dir).Fire()
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer hd.pool.release(client)
+
+	infos, err := client.ReadDir(dir)
+	if err != nil {
+		lg.Error().Msg("hdfs: list directory failed").Error("error", err).Fire()
+		return nil, err
+	}
+	files := make([]*FileInfo, 0, len(infos))
+	for _, info := range infos {
+		files = append(files, hdfsFileInfo(path.Join(dir, info.Name()), info))
+	}
+	return files, nil
+}
+
+func (hd *HDFS) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("hdfs: walk directory").String("root", root).Fire()
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer hd.pool.release(client)
+
+	err = client.Walk(root, func(name string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return fn(name, nil, err)
+		}
+		return fn(name, hdfsFileInfo(name, info), nil)
+	})
+	if err != nil && err != filepath.SkipDir {
+		lg.Error().Msg("hdfs: walk directory failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (hd *HDFS) CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (eTag string, err error) {
 	lg := glog.FromContext(ctx)
 	lg.Debug().Msg("hdfs: create new file for write").String("name", name).Fire()
-	writer, err := hd.client.Create(name)
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer hd.pool.release(client)
+
+	// Fail fast before writing data, the publish checks it again under the lock.
+	if err = checkNotExists(client.Client, name); err != nil {
+		lg.Error().Msg("hdfs: create new file failed").Error("error", err).Fire()
+		return "", err
+	}
+
+	// Write data into a staging file and rename it after all data written.
+	staging := stagingName(name)
+	writer, err := client.Create(staging)
 	if err != nil {
 		lg.Error().Msg("hdfs: create new file failed").Error("error", err).Fire()
 		return "", err
 	}
+	defer func() {
+		if err != nil {
+			_ = writer.Close()
+			_ = client.Remove(staging)
+		}
+	}()
 
 	h := md5.New()
 	buf := make([]byte, 4096)
@@ -151,19 +390,23 @@ LOOP:
 	if err = writer.Close(); err != nil {
 		return "", err
 	}
+	if err = hdfsRenameNoReplace(client.Client, staging, name); err != nil {
+		lg.Error().Msg("hdfs: publish staging file failed").Error("error", err).Fire()
+		return "", err
+	}
 
 	// Calculate the md5 as hex.
 	b := h.Sum(nil)
 	md5Hex := make([]byte, hex.EncodedLen(len(b)))
 	hex.Encode(md5Hex, b)
-	eTag := string(md5Hex)
+	eTag = string(md5Hex)
 	return eTag, nil
 }
 
 func (hd *HDFS) OpenForRead(ctx context.Context, name string) (io.ReadCloser, error) {
 	lg := glog.FromContext(ctx)
 	lg.Debug().Msg("hdfs: open file for read").String("name", name).Fire()
-	reader, err := hd.client.Open(name)
+	reader, err := hd.open(ctx, name)
 	if err != nil {
 		lg.Error().Msg("hdfs: open file failed").Error("error", err).Fire()
 		return nil, err
@@ -171,10 +414,51 @@ func (hd *HDFS) OpenForRead(ctx context.Context, name string) (io.ReadCloser, er
 	return reader, nil
 }
 
+func (hd *HDFS) OpenRange(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("hdfs: open file for range read").String("name", name).
+		Int64("offset", offset).Int64("length", length).Fire()
+	reader, err := hd.open(ctx, name)
+	if err != nil {
+		lg.Error().Msg("hdfs: open file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	if offset < 0 || offset > reader.Stat().Size() {
+		_ = reader.Close()
+		return nil, ErrInvalidRange
+	}
+	if _, err = reader.Seek(offset, io.SeekStart); err != nil {
+		lg.Error().Msg("hdfs: seek file failed").Error("error", err).Fire()
+		_ = reader.Close()
+		return nil, err
+	}
+	return limitRange(reader, length), nil
+}
+
+// open opens the file for read, the client will be released after the reader closed.
+func (hd *HDFS) open(ctx context.Context, name string) (*hdfsReader, error) {
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := client.Open(name)
+	if err != nil {
+		hd.pool.release(client)
+		return nil, err
+	}
+	return &hdfsReader{FileReader: reader, release: func() { hd.pool.release(client) }}, nil
+}
+
 func (hd *HDFS) Remove(ctx context.Context, name string) error {
 	lg := glog.FromContext(ctx)
 	lg.Debug().Msg("hdfs: remove file").String("name", name).Fire()
-	err := hd.client.Remove(name)
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer hd.pool.release(client)
+
+	err = client.Remove(name)
 	if err != nil {
 		lg.Error().Msg("hdfs: remove file failed").Error("error", err).Fire()
 		return err
@@ -185,7 +469,13 @@ func (hd *HDFS) Remove(ctx context.Context, name string) error {
 func (hd *HDFS) RemoveAll(ctx context.Context, name string) error {
 	lg := glog.FromContext(ctx)
 	lg.Debug().Msg("hdfs: remove dir and all children").String("name", name).Fire()
-	err := hd.client.RemoveAll(name)
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer hd.pool.release(client)
+
+	err = client.RemoveAll(name)
 	if err != nil {
 		lg.Error().Msg("hdfs: remove dir and all children failed").Error("error", err).Fire()
 		return err
@@ -196,10 +486,104 @@ func (hd *HDFS) RemoveAll(ctx context.Context, name string) error {
 func (hd *HDFS) Rename(ctx context.Context, oldName string, newName string) error {
 	lg := glog.FromContext(ctx)
 	lg.Debug().Msg("hdfs: rename file").String("old", oldName).String("new", newName).Fire()
-	err := hd.client.Rename(oldName, newName)
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer hd.pool.release(client)
+
+	err = client.Rename(oldName, newName)
+	if err != nil {
+		lg.Error().Msg("hdfs: rename file failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (hd *HDFS) RenameNoReplace(ctx context.Context, oldName string, newName string) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("hdfs: rename file without replace").String("old", oldName).String("new", newName).Fire()
+	client, err := hd.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer hd.pool.release(client)
+
+	err = hdfsRenameNoReplace(client.Client, oldName, newName)
 	if err != nil {
 		lg.Error().Msg("hdfs: rename file failed").Error("error", err).Fire()
 		return err
 	}
 	return nil
 }
+
+// renameLockExpire is the age that a rename lock is considered left by a crashed process.
+// The lock is held only to check and rename, that takes a few round trips to namenode.
+const renameLockExpire = time.Minute
+
+// checkNotExists returns an error that satisfies os.IsExist if name exists,
+// or the error of stat if it is not caused by name not exists.
+func checkNotExists(client *hdfs.Client, name string) error {
+	_, err := client.Stat(name)
+	if err == nil {
+		return &os.PathError{Op: "rename", Path: name, Err: os.ErrExist}
+	}
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// hdfsRenameNoReplace renames oldName to newName, fails with os.ErrExist if newName exists. The
+// rename of client overwrites the destination, so the check and rename are guarded by a lock
+// file next to newName. The lock is created exclusively by namenode, the concurrent renames
+// to the same name fail with os.ErrExist.
+func hdfsRenameNoReplace(client *hdfs.Client, oldName string, newName string) (err error) {
+	// The lock is named as a staging file, so it is hidden from List and removed by SweepStagingFiles.
+	lock := newName + stagingMarker + "lock"
+	if err = lockRename(client, lock, newName); err != nil {
+		return err
+	}
+	defer func() {
+		_ = client.Remove(lock)
+	}()
+	if err = checkNotExists(client, newName); err != nil {
+		return err
+	}
+	return client.Rename(oldName, newName)
+}
+
+// lockRename creates the lock file. The stale lock left by a crashed process is replaced.
+func lockRename(client *hdfs.Client, lock string, name string) error {
+	err := client.CreateEmptyFile(lock)
+	if err == nil || !isCreateConflict(err) {
+		return err
+	}
+	info, statErr := client.Stat(lock)
+	switch {
+	case os.IsNotExist(statErr):
+		// The lock released just now.
+	case statErr != nil:
+		return statErr
+	case time.Since(info.ModTime()) < renameLockExpire:
+		// Another rename to the same name is in progress, and it wins.
+		return &os.PathError{Op: "rename", Path: name, Err: os.ErrExist}
+	default:
+		if err = client.Remove(lock); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err = client.CreateEmptyFile(lock); err != nil && isCreateConflict(err) {
+		return &os.PathError{Op: "rename", Path: name, Err: os.ErrExist}
+	}
+	return err
+}
+
+// isCreateConflict reports whether the create failed because the file exists or is being created.
+func isCreateConflict(err error) bool {
+	if os.IsExist(err) {
+		return true
+	}
+	var remoteErr hdfs.Error
+	return errors.As(err, &remoteErr) && strings.HasSuffix(remoteErr.Exception(), "AlreadyBeingCreatedException")
+}
@@ -0,0 +1,152 @@
+package fileio
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/colinmarc/hdfs/v2"
+)
+
+// hdfsClient is a hdfs client in pool that acts as a specified user.
+type hdfsClient struct {
+	*hdfs.Client
+
+	user     string
+	refs     int
+	lastUsed time.Time
+	retired  bool // The retired client will be closed after all references released.
+}
+
+// hdfsPool holds the hdfs clients keyed by user. The least recently used client will be
+// retired if the number of clients exceeds the size. It is safe for concurrent use.
+type hdfsPool struct {
+	mu      sync.Mutex
+	options hdfs.ClientOptions
+	size    int
+	clients map[string]*hdfsClient
+}
+
+func newHdfsPool(options hdfs.ClientOptions, size int) *hdfsPool {
+	if size <= 0 {
+		size = 1
+	}
+	return &hdfsPool{
+		options: options,
+		size:    size,
+		clients: make(map[string]*hdfsClient),
+	}
+}
+
+// acquire returns the client of user, create a new one if not exists.
+// Must call release after the client no longer used.
+func (p *hdfsPool) acquire(user string) (*hdfsClient, error) {
+	p.mu.Lock()
+	if c, ok := p.clients[user]; ok {
+		c.refs++
+		c.lastUsed = time.Now()
+		p.mu.Unlock()
+		return c, nil
+	}
+	options := p.options
+	p.mu.Unlock()
+
+	// Create client without lock, because it needs to connect the namenode.
+	options.User = user
+	client, err := hdfs.NewClient(options)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Another goroutine may have created the client of same user.
+	if c, ok := p.clients[user]; ok {
+		_ = client.Close()
+		c.refs++
+		c.lastUsed = time.Now()
+		return c, nil
+	}
+	if len(p.clients) >= p.size {
+		p.evictLocked()
+	}
+	c := &hdfsClient{Client: client, user: user, refs: 1, lastUsed: time.Now()}
+	p.clients[user] = c
+	return c, nil
+}
+
+// release decrease the reference of client, and close it if it is retired and no longer used.
+func (p *hdfsPool) release(c *hdfsClient) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c.refs--
+	if c.retired && c.refs == 0 {
+		_ = c.Close()
+	}
+}
+
+// evictLocked retires the least recently used client. Must be called with p.mu held.
+func (p *hdfsPool) evictLocked() {
+	var lru *hdfsClient
+	for _, c := range p.clients {
+		if lru == nil || c.lastUsed.Before(lru.lastUsed) {
+			lru = c
+		}
+	}
+	if lru != nil {
+		p.retireLocked(lru)
+	}
+}
+
+func (p *hdfsPool) retireLocked(c *hdfsClient) {
+	delete(p.clients, c.user)
+	c.retired = true
+	if c.refs == 0 {
+		_ = c.Close()
+	}
+}
+
+// reset retires all clients and uses the new options to create clients later.
+func (p *hdfsPool) reset(options hdfs.ClientOptions) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.options = options
+	for _, c := range p.clients {
+		p.retireLocked(c)
+	}
+}
+
+// getOptions returns the options used to create clients.
+func (p *hdfsPool) getOptions() hdfs.ClientOptions {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.options
+}
+
+// close closes all clients in pool.
+func (p *hdfsPool) close() (err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for user, c := range p.clients {
+		delete(p.clients, user)
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return
+}
+
+// hdfsReader release the client after the reader closed.
+type hdfsReader struct {
+	*hdfs.FileReader
+	closeOnce sync.Once
+	release   func()
+}
+
+var _ io.ReadSeeker = (*hdfsReader)(nil)
+
+func (r *hdfsReader) Close() error {
+	err := r.FileReader.Close()
+	r.closeOnce.Do(r.release)
+	return err
+}
@@ -0,0 +1,169 @@
+package fileio
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/colinmarc/hdfs/v2"
+)
+
+// fakeNamenode accepts the connections of hdfs clients and discards the handshake,
+// so the pool can be tested without a namenode.
+type fakeNamenode struct {
+	mu    sync.Mutex
+	conns []*fakeNamenodeConn
+}
+
+type fakeNamenodeConn struct {
+	net.Conn
+	mu     sync.Mutex
+	closed bool
+}
+
+func (c *fakeNamenodeConn) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	return c.Conn.Close()
+}
+
+func (c *fakeNamenodeConn) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (n *fakeNamenode) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	client, server := net.Pipe()
+	go func() { _, _ = io.Copy(io.Discard, server) }()
+	conn := &fakeNamenodeConn{Conn: client}
+	n.mu.Lock()
+	n.conns = append(n.conns, conn)
+	n.mu.Unlock()
+	return conn, nil
+}
+
+func (n *fakeNamenode) dials() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.conns)
+}
+
+// conn returns the connection of the i-th created client.
+func (n *fakeNamenode) conn(i int) *fakeNamenodeConn {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.conns[i]
+}
+
+func newTestHdfsPool(size int) (*hdfsPool, *fakeNamenode) {
+	namenode := new(fakeNamenode)
+	options := hdfs.ClientOptions{Addresses: []string{"namenode:8020"}, NamenodeDialFunc: namenode.dial}
+	return newHdfsPool(options, size), namenode
+}
+
+func acquireClient(t *testing.T, p *hdfsPool, user string) *hdfsClient {
+	t.Helper()
+	c, err := p.acquire(user)
+	if err != nil {
+		t.Fatalf("acquire %s: %v", user, err)
+	}
+	return c
+}
+
+func TestHdfsPoolReuse(t *testing.T) {
+	p, namenode := newTestHdfsPool(2)
+	defer func() { _ = p.close() }()
+
+	tests := []struct {
+		user  string
+		dials int
+	}{
+		{user: "alice", dials: 1},
+		{user: "alice", dials: 1},
+		{user: "bob", dials: 2},
+		{user: "alice", dials: 2},
+	}
+	for _, tt := range tests {
+		c := acquireClient(t, p, tt.user)
+		if c.user != tt.user {
+			t.Fatalf("user = %q, want %q", c.user, tt.user)
+		}
+		p.release(c)
+		if got := namenode.dials(); got != tt.dials {
+			t.Fatalf("acquire %q: dials = %d, want %d", tt.user, got, tt.dials)
+		}
+	}
+}
+
+func TestHdfsPoolEvictLeastRecentlyUsed(t *testing.T) {
+	p, namenode := newTestHdfsPool(2)
+	defer func() { _ = p.close() }()
+
+	alice := acquireClient(t, p, "alice")
+	bob := acquireClient(t, p, "bob")
+	p.release(bob)
+	// The alice is least recently used but still in use, so it is retired without closed.
+	carol := acquireClient(t, p, "carol")
+	p.release(carol)
+	if !alice.retired || namenode.conn(0).isClosed() {
+		t.Fatalf("alice: retired = %v, closed = %v", alice.retired, namenode.conn(0).isClosed())
+	}
+	if _, ok := p.clients["alice"]; ok {
+		t.Fatal("the retired client is still in pool")
+	}
+	p.release(alice)
+	if !namenode.conn(0).isClosed() {
+		t.Fatal("the retired client is not closed after released")
+	}
+
+	// The bob is least recently used and not in use, so it is closed at once.
+	dave := acquireClient(t, p, "dave")
+	p.release(dave)
+	if !bob.retired || !namenode.conn(1).isClosed() {
+		t.Fatalf("bob: retired = %v, closed = %v", bob.retired, namenode.conn(1).isClosed())
+	}
+	if len(p.clients) != 2 {
+		t.Fatalf("clients = %d, want 2", len(p.clients))
+	}
+}
+
+func TestHdfsPoolReset(t *testing.T) {
+	p, namenode := newTestHdfsPool(4)
+	defer func() { _ = p.close() }()
+
+	inUse := acquireClient(t, p, "alice")
+	idle := acquireClient(t, p, "bob")
+	p.release(idle)
+
+	options := p.getOptions()
+	options.UseDatanodeHostname = true
+	p.reset(options)
+	if !p.getOptions().UseDatanodeHostname {
+		t.Fatal("the options are not replaced")
+	}
+	if namenode.conn(0).isClosed() || !namenode.conn(1).isClosed() {
+		t.Fatalf("closed = %v, %v, want false, true", namenode.conn(0).isClosed(), namenode.conn(1).isClosed())
+	}
+
+	// The new client is created with the new options.
+	c := acquireClient(t, p, "alice")
+	if c == inUse || namenode.dials() != 3 {
+		t.Fatalf("the retired client is reused, dials = %d", namenode.dials())
+	}
+	p.release(c)
+	p.release(inUse)
+	if !namenode.conn(0).isClosed() {
+		t.Fatal("the retired client is not closed after released")
+	}
+
+	if err := p.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if !namenode.conn(2).isClosed() || len(p.clients) != 0 {
+		t.Fatal("the clients are not closed")
+	}
+}
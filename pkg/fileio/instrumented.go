@@ -0,0 +1,161 @@
+package fileio
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The results of storage operation in metrics.
+const (
+	opResultOK       = "ok"
+	opResultNotFound = "not_found"
+	opResultExists   = "exists"
+	opResultError    = "error"
+)
+
+var (
+	storageOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "resourcemanager",
+		Subsystem: "storage",
+		Name:      "operations_total",
+		Help:      "The number of storage operations by backend, operation and result.",
+	}, []string{"backend", "operation", "result"})
+
+	storageOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "resourcemanager",
+		Subsystem: "storage",
+		Name:      "operation_duration_seconds",
+		Help:      "The latency of storage operations by backend and operation. The duration of read is the time to open.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"backend", "operation"})
+
+	storageBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "resourcemanager",
+		Subsystem: "storage",
+		Name:      "bytes_total",
+		Help:      "The bytes of data read from or written to storage by backend and direction.",
+	}, []string{"backend", "direction"})
+)
+
+// Instrumented is a FileIO that records the prometheus metrics of operations.
+type Instrumented struct {
+	fs      FileIO
+	backend string
+}
+
+var _ FileIO = (*Instrumented)(nil)
+
+// NewInstrumented wraps fs to record metrics with the backend label.
+func NewInstrumented(fs FileIO, backend string) *Instrumented {
+	return &Instrumented{fs: fs, backend: backend}
+}
+
+// observe records the operation that started at start with the err returned.
+func (i *Instrumented) observe(operation string, start time.Time, err error) {
+	result := opResultOK
+	switch {
+	case err == nil:
+	case os.IsNotExist(err):
+		result = opResultNotFound
+	case os.IsExist(err):
+		result = opResultExists
+	default:
+		result = opResultError
+	}
+	storageOperations.WithLabelValues(i.backend, operation, result).Inc()
+	storageOperationDuration.WithLabelValues(i.backend, operation).Observe(time.Since(start).Seconds())
+}
+
+func (i *Instrumented) Close() error {
+	return i.fs.Close()
+}
+
+func (i *Instrumented) Ping(ctx context.Context) (err error) {
+	defer func(start time.Time) { i.observe("ping", start, err) }(time.Now())
+	return Ping(ctx, i.fs)
+}
+
+func (i *Instrumented) MkdirAll(ctx context.Context, dirname string, perm os.FileMode) (err error) {
+	defer func(start time.Time) { i.observe("mkdir_all", start, err) }(time.Now())
+	return i.fs.MkdirAll(ctx, dirname, perm)
+}
+
+func (i *Instrumented) IsExists(ctx context.Context, name string) (exists bool, err error) {
+	defer func(start time.Time) { i.observe("is_exists", start, err) }(time.Now())
+	return i.fs.IsExists(ctx, name)
+}
+
+func (i *Instrumented) Stat(ctx context.Context, name string) (info *FileInfo, err error) {
+	defer func(start time.Time) { i.observe("stat", start, err) }(time.Now())
+	return i.fs.Stat(ctx, name)
+}
+
+func (i *Instrumented) List(ctx context.Context, dir string) (infos []*FileInfo, err error) {
+	defer func(start time.Time) { i.observe("list", start, err) }(time.Now())
+	return i.fs.List(ctx, dir)
+}
+
+// Walk is not recorded, because its duration includes the time spent in fn.
+func (i *Instrumented) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	return i.fs.Walk(ctx, root, fn)
+}
+
+func (i *Instrumented) CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (eTag string, err error) {
+	defer func(start time.Time) { i.observe("create_and_write", start, err) }(time.Now())
+	return i.fs.CreateAndWrite(ctx, name, &countingReader{ReadCloser: reader, counter: storageBytes.WithLabelValues(i.backend, "write")})
+}
+
+func (i *Instrumented) OpenForRead(ctx context.Context, name string) (reader io.ReadCloser, err error) {
+	defer func(start time.Time) { i.observe("open_for_read", start, err) }(time.Now())
+	if reader, err = i.fs.OpenForRead(ctx, name); err != nil {
+		return nil, err
+	}
+	return &countingReader{ReadCloser: reader, counter: storageBytes.WithLabelValues(i.backend, "read")}, nil
+}
+
+func (i *Instrumented) OpenRange(ctx context.Context, name string, offset int64, length int64) (reader io.ReadCloser, err error) {
+	defer func(start time.Time) { i.observe("open_range", start, err) }(time.Now())
+	if reader, err = i.fs.OpenRange(ctx, name, offset, length); err != nil {
+		return nil, err
+	}
+	return &countingReader{ReadCloser: reader, counter: storageBytes.WithLabelValues(i.backend, "read")}, nil
+}
+
+func (i *Instrumented) Remove(ctx context.Context, name string) (err error) {
+	defer func(start time.Time) { i.observe("remove", start, err) }(time.Now())
+	return i.fs.Remove(ctx, name)
+}
+
+func (i *Instrumented) RemoveAll(ctx context.Context, name string) (err error) {
+	defer func(start time.Time) { i.observe("remove_all", start, err) }(time.Now())
+	return i.fs.RemoveAll(ctx, name)
+}
+
+func (i *Instrumented) Rename(ctx context.Context, oldName string, newName string) (err error) {
+	defer func(start time.Time) { i.observe("rename", start, err) }(time.Now())
+	return i.fs.Rename(ctx, oldName, newName)
+}
+
+func (i *Instrumented) RenameNoReplace(ctx context.Context, oldName string, newName string) (err error) {
+	defer func(start time.Time) { i.observe("rename_no_replace", start, err) }(time.Now())
+	return RenameNoReplace(ctx, i.fs, oldName, newName)
+}
+
+// countingReader adds the bytes read to counter.
+type countingReader struct {
+	io.ReadCloser
+	counter prometheus.Counter
+}
+
+func (r *countingReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	if n > 0 {
+		r.counter.Add(float64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,80 @@
+package fileio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// removeFailedFS fails all Remove with a storage error.
+type removeFailedFS struct {
+	FileIO
+}
+
+func (fs *removeFailedFS) Remove(ctx context.Context, name string) error {
+	return errors.New("remove failed")
+}
+
+func TestInstrumentedLabels(t *testing.T) {
+	// The backend label is unique for the test, so the metrics start from 0.
+	const backend = "test-instrumented"
+	m := newTestMemory(t, 0)
+	i := NewInstrumented(&removeFailedFS{FileIO: m}, backend)
+	ctx := testContext()
+
+	writeString(t, i, "/dir/a", "hello")
+	writeString(t, m, "/dir/b", "world")
+	if err := RenameNoReplace(ctx, i, "/dir/b", "/dir/a"); err == nil {
+		t.Fatal("expected error to rename to an existing file")
+	}
+	if got := readString(t, i, "/dir/a"); got != "hello" {
+		t.Fatalf("read = %q", got)
+	}
+	reader, err := i.OpenRange(ctx, "/dir/a", 1, 2)
+	if err != nil {
+		t.Fatalf("open range: %v", err)
+	}
+	_, _ = io.ReadAll(reader)
+	_ = reader.Close()
+	if _, err = i.Stat(ctx, "/dir/none"); err == nil {
+		t.Fatal("expected error to stat a missing file")
+	}
+	if err = i.Remove(ctx, "/dir/a"); err == nil {
+		t.Fatal("expected error to remove")
+	}
+	// Walk is not recorded.
+	_ = i.Walk(ctx, "/dir", func(name string, info *FileInfo, err error) error { return nil })
+
+	operations := []struct {
+		operation string
+		result    string
+		count     float64
+	}{
+		{operation: "create_and_write", result: opResultOK, count: 1},
+		{operation: "rename_no_replace", result: opResultExists, count: 1},
+		{operation: "open_for_read", result: opResultOK, count: 1},
+		{operation: "open_range", result: opResultOK, count: 1},
+		{operation: "stat", result: opResultNotFound, count: 1},
+		{operation: "remove", result: opResultError, count: 1},
+		{operation: "walk", result: opResultOK, count: 0},
+	}
+	for _, tt := range operations {
+		if got := testutil.ToFloat64(storageOperations.WithLabelValues(backend, tt.operation, tt.result)); got != tt.count {
+			t.Fatalf("%s %s = %v, want %v", tt.operation, tt.result, got, tt.count)
+		}
+	}
+	if n := testutil.CollectAndCount(storageOperationDuration, "resourcemanager_storage_operation_duration_seconds"); n == 0 {
+		t.Fatal("no duration recorded")
+	}
+
+	// The bytes read include the range read.
+	directions := map[string]float64{"write": 5, "read": 7}
+	for direction, want := range directions {
+		if got := testutil.ToFloat64(storageBytes.WithLabelValues(backend, direction)); got != want {
+			t.Fatalf("%s bytes = %v, want %v", direction, got, want)
+		}
+	}
+}
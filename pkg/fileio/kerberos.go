@@ -0,0 +1,81 @@
+package fileio
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	krbclient "github.com/jcmturner/gokrb5/v8/client"
+	krbconfig "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+)
+
+// KerberosConfig is the configuration for login the kerberized HDFS cluster.
+// One of the KeytabPath and CCachePath must be specified.
+type KerberosConfig struct {
+	// The principal of client, eg: "resourcemanager/host@EXAMPLE.COM". Is required when use keytab.
+	// The default realm in krb5.conf will be used if the realm not specified.
+	Principal string `json:"principal" yaml:"principal" env:"PRINCIPAL" validate:"required_with=KeytabPath"`
+
+	// The path of keytab file.
+	KeytabPath string `json:"keytab_path" yaml:"keytab_path" env:"KEYTAB_PATH" validate:"required_without=CCachePath"`
+
+	// The path of credential cache file, it is usually created by kinit.
+	// The ccache will be reloaded when renewal, so it must be refreshed by other process such as k5start.
+	CCachePath string `json:"ccache_path" yaml:"ccache_path" env:"CCACHE_PATH" validate:"required_without=KeytabPath"`
+
+	// The path of krb5.conf.
+	Krb5ConfPath string `json:"krb5_conf_path" yaml:"krb5_conf_path" env:"KRB5_CONF_PATH,default=/etc/krb5.conf" validate:"required"`
+
+	// The service principal name of namenode, eg: "nn/_HOST". Overwrite the
+	// `dfs.namenode.kerberos.principal` in hadoop config if specified.
+	ServicePrincipalName string `json:"service_principal_name" yaml:"service_principal_name" env:"SERVICE_PRINCIPAL_NAME" validate:"-"`
+
+	// The interval to renew the kerberos ticket.
+	RenewInterval time.Duration `json:"renew_interval" yaml:"renew_interval" env:"RENEW_INTERVAL,default=1h" validate:"gt=0"`
+}
+
+// useKeytab reports whether to login by keytab.
+func (cfg *KerberosConfig) useKeytab() bool {
+	return cfg.KeytabPath != ""
+}
+
+// newKerberosClient create a kerberos client and login by keytab or ccache.
+func newKerberosClient(cfg *KerberosConfig) (*krbclient.Client, error) {
+	if cfg == nil {
+		return nil, errors.New("kerberos enabled in hadoop config, but kerberos config is not provided")
+	}
+
+	krb5conf, err := krbconfig.Load(cfg.Krb5ConfPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.useKeytab() {
+		ccache, err := credentials.LoadCCache(cfg.CCachePath)
+		if err != nil {
+			return nil, err
+		}
+		return krbclient.NewFromCCache(ccache, krb5conf, krbclient.DisablePAFXFAST(true))
+	}
+
+	kt, err := keytab.Load(cfg.KeytabPath)
+	if err != nil {
+		return nil, err
+	}
+	username, realm := splitPrincipal(cfg.Principal, krb5conf.LibDefaults.DefaultRealm)
+	client := krbclient.NewWithKeytab(username, realm, kt, krb5conf, krbclient.DisablePAFXFAST(true))
+	if err = client.Login(); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// splitPrincipal splits the principal into username and realm, the defaultRealm is used if the realm not specified.
+func splitPrincipal(principal string, defaultRealm string) (username string, realm string) {
+	if i := strings.LastIndex(principal, "@"); i >= 0 {
+		return principal[:i], principal[i+1:]
+	}
+	return principal, defaultRealm
+}
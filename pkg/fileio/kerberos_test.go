@@ -0,0 +1,138 @@
+package fileio
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jcmturner/gokrb5/v8/iana/etypeID"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+)
+
+// The KDC is not reachable, so the login by keytab fails after the files loaded.
+const testKrb5Conf = `[libdefaults]
+  default_realm = EXAMPLE.COM
+  udp_preference_limit = 1
+
+[realms]
+  EXAMPLE.COM = {
+    kdc = 127.0.0.1:1
+  }
+`
+
+// writeKerberosFiles writes the krb5.conf and a keytab of principal to dir.
+func writeKerberosFiles(t *testing.T, dir string, principal string) (string, string) {
+	t.Helper()
+	krb5Conf := filepath.Join(dir, "krb5.conf")
+	if err := os.WriteFile(krb5Conf, []byte(testKrb5Conf), 0600); err != nil {
+		t.Fatalf("write krb5.conf: %v", err)
+	}
+	kt := keytab.New()
+	username, realm := splitPrincipal(principal, "EXAMPLE.COM")
+	if err := kt.AddEntry(username, realm, "password", time.Now(), 1, etypeID.AES256_CTS_HMAC_SHA1_96); err != nil {
+		t.Fatalf("add keytab entry: %v", err)
+	}
+	data, err := kt.Marshal()
+	if err != nil {
+		t.Fatalf("marshal keytab: %v", err)
+	}
+	keytabPath := filepath.Join(dir, "client.keytab")
+	if err = os.WriteFile(keytabPath, data, 0600); err != nil {
+		t.Fatalf("write keytab: %v", err)
+	}
+	return krb5Conf, keytabPath
+}
+
+func TestNewKerberosClientFailed(t *testing.T) {
+	dir := t.TempDir()
+	krb5Conf, keytabPath := writeKerberosFiles(t, dir, "rm/host@EXAMPLE.COM")
+	missing := filepath.Join(dir, "missing")
+	tests := []struct {
+		name string
+		cfg  *KerberosConfig
+		err  string
+	}{
+		{name: "no config", err: "kerberos config is not provided"},
+		{
+			name: "krb5.conf not exists",
+			cfg:  &KerberosConfig{KeytabPath: keytabPath, Principal: "rm/host", Krb5ConfPath: missing},
+			err:  "missing",
+		},
+		{
+			name: "ccache not exists",
+			cfg:  &KerberosConfig{CCachePath: missing, Krb5ConfPath: krb5Conf},
+			err:  "missing",
+		},
+		{
+			name: "keytab not exists",
+			cfg:  &KerberosConfig{KeytabPath: missing, Principal: "rm/host", Krb5ConfPath: krb5Conf},
+			err:  "missing",
+		},
+		{
+			name: "kdc unreachable",
+			cfg:  &KerberosConfig{KeytabPath: keytabPath, Principal: "rm/host@EXAMPLE.COM", Krb5ConfPath: krb5Conf},
+			err:  "KDC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newKerberosClient(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("error = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSplitPrincipal(t *testing.T) {
+	tests := []struct {
+		principal string
+		username  string
+		realm     string
+	}{
+		{principal: "rm/host@EXAMPLE.COM", username: "rm/host", realm: "EXAMPLE.COM"},
+		{principal: "rm/host", username: "rm/host", realm: "DEFAULT.COM"},
+		{principal: "rm@host@EXAMPLE.COM", username: "rm@host", realm: "EXAMPLE.COM"},
+	}
+	for _, tt := range tests {
+		if username, realm := splitPrincipal(tt.principal, "DEFAULT.COM"); username != tt.username || realm != tt.realm {
+			t.Fatalf("split %s = %s, %s", tt.principal, username, realm)
+		}
+	}
+}
+
+func TestKerberosConfigValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   KerberosConfig
+		valid bool
+	}{
+		{name: "keytab", cfg: KerberosConfig{KeytabPath: "/a.keytab", Principal: "rm"}, valid: true},
+		{name: "ccache", cfg: KerberosConfig{CCachePath: "/tmp/krb5cc"}, valid: true},
+		{name: "keytab without principal", cfg: KerberosConfig{KeytabPath: "/a.keytab"}},
+		{name: "neither keytab nor ccache", cfg: KerberosConfig{Principal: "rm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Krb5ConfPath = "/etc/krb5.conf"
+			tt.cfg.RenewInterval = time.Hour
+			if err := validator.New().Struct(&tt.cfg); (err == nil) != tt.valid {
+				t.Fatalf("validate: %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestNewHadoopClientKerberosRequired(t *testing.T) {
+	confMap := map[string]string{
+		"fs.defaultFS":                   "hdfs://127.0.0.1:1",
+		"hadoop.security.authentication": "kerberos",
+	}
+	_, err := NewHadoopClientFromConfMap(testContext(), confMap, "", nil, 1)
+	if err == nil || !strings.Contains(err.Error(), "kerberos config is not provided") {
+		t.Fatalf("error = %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+package fileio
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+
+	"github.com/DataWorkbench/glog"
+)
+
+var _ FileIO = (*Local)(nil)
+
+// Local implements the FileIO by local filesystem. All files are stored under the rootDir.
+type Local struct {
+	rootDir string
+}
+
+func NewLocal(ctx context.Context, rootDir string) (*Local, error) {
+	lg := glog.FromContext(ctx)
+
+	rootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	lg.Info().Msg("local: creating new client").String("rootDir", rootDir).Fire()
+
+	if err = os.MkdirAll(rootDir, 0777); err != nil {
+		lg.Error().Msg("local: create root directory failed").Error("error", err).Fire()
+		return nil, err
+	}
+	return &Local{rootDir: rootDir}, nil
+}
+
+// realPath converts the name to the path in local filesystem.
+// The name is always treated as rooted at rootDir, so `..` can not escape it.
+func (l *Local) realPath(name string) string {
+	return filepath.Join(l.rootDir, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+func (l *Local) Close() error {
+	return nil
+}
+
+func (l *Local) MkdirAll(ctx context.Context, dirname string, perm os.FileMode) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: create directory recursively").
+		String("dirname", dirname).
+		Uint32("perm", uint32(perm)).Fire()
+	err := os.MkdirAll(l.realPath(dirname), perm)
+	if err != nil {
+		lg.Error().Msg("local: create directory recursively failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (l *Local) IsExists(ctx context.Context, name string) (bool, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: check file is exists").String("name", name).Fire()
+	_, err := os.Stat(l.realPath(name))
+	if err != nil {
+		lg.Warn().Msg("local: stat file failed").Error("error", err).Fire()
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *Local) Stat(ctx context.Context, name string) (*FileInfo, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: stat file").String("name", name).Fire()
+	info, err := os.Stat(l.realPath(name))
+	if err != nil {
+		lg.Warn().Msg("local: stat file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	return localFileInfo(name, info), nil
+}
+
+func localFileInfo(name string, info os.FileInfo) *FileInfo {
+	fi := &FileInfo{
+		Name:    name,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+		IsDir:   info.IsDir(),
+		Extra: map[string]string{
+			"permission": info.Mode().Perm().String(),
+		},
+	}
+	if info.IsDir() {
+		fi.Size = 0
+	}
+	return fi
+}
+
+func (l *Local) List(ctx context.Context, dir string) ([]*FileInfo, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: list directory").String("dir", dir).Fire()
+	entries, err := os.ReadDir(l.realPath(dir))
+	if err != nil {
+		lg.Error().Msg("local: list directory failed").Error("error", err).Fire()
+		return nil, err
+	}
+	files := make([]*FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			// The file may be removed after read dir.
+			if os.IsNotExist(err) {
+				continue
+			}
+			lg.Error().Msg("local: list directory failed").Error("error", err).Fire()
+			return nil, err
+		}
+		files = append(files, localFileInfo(path.Join(dir, entry.Name()), info))
+	}
+	return files, nil
+}
+
+func (l *Local) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: walk directory").String("root", root).Fire()
+	realRoot := l.realPath(root)
+	err := filepath.Walk(realRoot, func(realPath string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		rel, relErr := filepath.Rel(realRoot, realPath)
+		if relErr != nil {
+			return relErr
+		}
+		name := path.Join(root, filepath.ToSlash(rel))
+		if err != nil {
+			return fn(name, nil, err)
+		}
+		return fn(name, localFileInfo(name, info), nil)
+	})
+	if err != nil {
+		lg.Error().Msg("local: walk directory failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (l *Local) CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (eTag string, err error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: create new file for write").String("name", name).Fire()
+
+	// Same as HDFS, the parent directory must be exists and the file must not.
+	// Fail fast before writing data, the publish does not overwrite an existing file.
+	realPath := l.realPath(name)
+	if _, err = os.Lstat(realPath); err == nil {
+		err = &os.PathError{Op: "create", Path: realPath, Err: os.ErrExist}
+		lg.Error().Msg("local: create new file failed").Error("error", err).Fire()
+		return "", err
+	}
+
+	// Write data into a staging file and rename it after all data written.
+	staging := stagingName(realPath)
+	writer, err := os.OpenFile(staging, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		lg.Error().Msg("local: create new file failed").Error("error", err).Fire()
+		return "", err
+	}
+	defer func() {
+		_ = writer.Close()
+		if err != nil {
+			_ = os.Remove(staging)
+		}
+	}()
+
+	h := md5.New()
+	if _, err = io.Copy(io.MultiWriter(writer, h), reader); err != nil {
+		lg.Error().Msg("local: write data to file failed").Error("error", err).Fire()
+		return "", err
+	}
+	if err = writer.Sync(); err != nil {
+		return "", err
+	}
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+	if err = localRenameNoReplace(staging, realPath); err != nil {
+		lg.Error().Msg("local: publish staging file failed").Error("error", err).Fire()
+		return "", err
+	}
+
+	// Calculate the md5 as hex.
+	eTag = hex.EncodeToString(h.Sum(nil))
+	return eTag, nil
+}
+
+func (l *Local) OpenForRead(ctx context.Context, name string) (io.ReadCloser, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: open file for read").String("name", name).Fire()
+	reader, err := os.Open(l.realPath(name))
+	if err != nil {
+		lg.Error().Msg("local: open file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	return reader, nil
+}
+
+func (l *Local) OpenRange(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: open file for range read").String("name", name).
+		Int64("offset", offset).Int64("length", length).Fire()
+	reader, err := os.Open(l.realPath(name))
+	if err != nil {
+		lg.Error().Msg("local: open file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	info, err := reader.Stat()
+	if err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+	if offset < 0 || offset > info.Size() {
+		_ = reader.Close()
+		return nil, ErrInvalidRange
+	}
+	if _, err = reader.Seek(offset, io.SeekStart); err != nil {
+		lg.Error().Msg("local: seek file failed").Error("error", err).Fire()
+		_ = reader.Close()
+		return nil, err
+	}
+	return limitRange(reader, length), nil
+}
+
+func (l *Local) Remove(ctx context.Context, name string) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: remove file").String("name", name).Fire()
+	err := os.Remove(l.realPath(name))
+	if err != nil {
+		lg.Error().Msg("local: remove file failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (l *Local) RemoveAll(ctx context.Context, name string) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: remove dir and all children").String("name", name).Fire()
+	err := os.RemoveAll(l.realPath(name))
+	if err != nil {
+		lg.Error().Msg("local: remove dir and all children failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Rename(ctx context.Context, oldName string, newName string) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: rename file").String("old", oldName).String("new", newName).Fire()
+	err := os.Rename(l.realPath(oldName), l.realPath(newName))
+	if err != nil {
+		lg.Error().Msg("local: rename file failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (l *Local) RenameNoReplace(ctx context.Context, oldName string, newName string) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("local: rename file without replace").String("old", oldName).String("new", newName).Fire()
+	err := localRenameNoReplace(l.realPath(oldName), l.realPath(newName))
+	if err != nil {
+		lg.Error().Msg("local: rename file failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+// localRenameNoReplace renames oldPath to newPath, fails with os.ErrExist if newPath exists.
+// A file is linked to newPath then unlinked from oldPath, link never replaces the target.
+// A directory can not be linked, so newPath is claimed by an empty directory first and
+// the rename replaces it. The os.Rename refuses to replace a directory, so rename(2) is called.
+func localRenameNoReplace(oldPath string, newPath string) error {
+	info, err := os.Lstat(oldPath)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		if err = os.Link(oldPath, newPath); err != nil {
+			return err
+		}
+		return os.Remove(oldPath)
+	}
+	if err = os.Mkdir(newPath, info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err = syscall.Rename(oldPath, newPath); err != nil {
+		_ = os.Remove(newPath)
+		return &os.LinkError{Op: "rename", Old: oldPath, New: newPath, Err: err}
+	}
+	return nil
+}
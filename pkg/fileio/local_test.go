@@ -0,0 +1,117 @@
+package fileio
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestLocal(t *testing.T) *Local {
+	t.Helper()
+	l, err := NewLocal(testContext(), t.TempDir())
+	if err != nil {
+		t.Fatalf("new local: %v", err)
+	}
+	return l
+}
+
+func TestNewLocalFailed(t *testing.T) {
+	// The root directory can not be created under a file.
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0666); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if _, err := NewLocal(testContext(), filepath.Join(file, "root")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestLocalCreateAndWrite(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := testContext()
+	if err := l.MkdirAll(ctx, "/dir", 0777); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	sum := md5.Sum([]byte("hello"))
+	if eTag := writeString(t, l, "/dir/a", "hello"); eTag != hex.EncodeToString(sum[:]) {
+		t.Fatalf("eTag = %s", eTag)
+	}
+	if got := readString(t, l, "/dir/a"); got != "hello" {
+		t.Fatalf("read = %q", got)
+	}
+	data, err := os.ReadFile(filepath.Join(l.rootDir, "dir", "a"))
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("file in root = %q, %v", data, err)
+	}
+
+	tests := []struct {
+		name  string
+		check func(error) bool
+	}{
+		{name: "/dir/a", check: os.IsExist},
+		// Same as HDFS, the parent directory is not created.
+		{name: "/none/a", check: os.IsNotExist},
+	}
+	for _, tt := range tests {
+		if _, err = l.CreateAndWrite(ctx, tt.name, io.NopCloser(strings.NewReader("x"))); !tt.check(err) {
+			t.Fatalf("create %s: unexpected error %v", tt.name, err)
+		}
+	}
+	if got := readString(t, l, "/dir/a"); got != "hello" {
+		t.Fatalf("read after create again = %q", got)
+	}
+}
+
+func TestLocalRealPath(t *testing.T) {
+	l := newTestLocal(t)
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "/dir/a", want: "dir/a"},
+		{name: "dir/a", want: "dir/a"},
+		{name: "/", want: ""},
+		// The name can not escape the root directory.
+		{name: "/../a", want: "a"},
+		{name: "/dir/../../../a", want: "a"},
+	}
+	for _, tt := range tests {
+		if got := l.realPath(tt.name); got != filepath.Join(l.rootDir, tt.want) {
+			t.Fatalf("real path of %s = %s", tt.name, got)
+		}
+	}
+}
+
+func TestLocalRemove(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := testContext()
+	if err := l.MkdirAll(ctx, "/dir/sub", 0777); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeString(t, l, "/dir/a", "a")
+	writeString(t, l, "/dir/sub/b", "b")
+
+	if err := l.Remove(ctx, "/dir/a"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := l.Remove(ctx, "/dir/a"); !os.IsNotExist(err) {
+		t.Fatalf("remove not exists: %v", err)
+	}
+	if err := l.Remove(ctx, "/dir"); err == nil {
+		t.Fatal("expected error to remove a non-empty directory")
+	}
+	if err := l.RemoveAll(ctx, "/dir"); err != nil {
+		t.Fatalf("remove all: %v", err)
+	}
+	if ok, err := l.IsExists(ctx, "/dir"); err != nil || ok {
+		t.Fatalf("is exists = %v, %v", ok, err)
+	}
+	if err := l.RemoveAll(ctx, "/dir"); err != nil {
+		t.Fatalf("remove all not exists: %v", err)
+	}
+}
@@ -0,0 +1,437 @@
+package fileio
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/DataWorkbench/glog"
+)
+
+var _ FileIO = (*Memory)(nil)
+
+type memNode struct {
+	isDir    bool
+	perm     os.FileMode
+	modTime  time.Time
+	data     []byte // The data is never modified after written, so can be shared by readers.
+	eTag     string
+	children map[string]*memNode
+}
+
+func newMemDir(perm os.FileMode) *memNode {
+	return &memNode{isDir: true, perm: perm, modTime: time.Now(), children: make(map[string]*memNode)}
+}
+
+// memReader is the io.ReadCloser of file in memory, it also implements io.Seeker.
+type memReader struct {
+	*bytes.Reader
+}
+
+func (r *memReader) Close() error {
+	return nil
+}
+
+// Memory implements the FileIO by memory. It is safe for concurrent use.
+// All data will be lost after the process exits.
+type Memory struct {
+	mu       sync.RWMutex
+	root     *memNode
+	used     int64
+	capacity int64 // The max bytes of all files, 0 means unlimited.
+}
+
+// NewMemory create a in-memory FileIO. The capacity is the max bytes of
+// all files can be stored, 0 means unlimited.
+func NewMemory(ctx context.Context, capacity int64) (*Memory, error) {
+	lg := glog.FromContext(ctx)
+	lg.Info().Msg("memory: creating new client").Int64("capacity", capacity).Fire()
+	if capacity < 0 {
+		return nil, errors.New("memory: capacity cannot be negative")
+	}
+	m := &Memory{
+		root:     newMemDir(0777),
+		capacity: capacity,
+	}
+	return m, nil
+}
+
+// splitPath returns the elements of cleaned name, the root returns empty slice.
+func (m *Memory) splitPath(name string) []string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, "/")
+}
+
+// lookup returns the node of elems. Returns nil if not found.
+func (m *Memory) lookup(elems []string) *memNode {
+	node := m.root
+	for _, elem := range elems {
+		if !node.isDir {
+			return nil
+		}
+		if node = node.children[elem]; node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+// lookupParent returns the parent directory of the elems.
+func (m *Memory) lookupParent(op string, name string, elems []string) (*memNode, error) {
+	parent := m.lookup(elems[:len(elems)-1])
+	if parent == nil {
+		return nil, &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	if !parent.isDir {
+		return nil, &os.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+	return parent, nil
+}
+
+// sizeOf returns the total bytes of files in the node.
+func (m *Memory) sizeOf(node *memNode) int64 {
+	if !node.isDir {
+		return int64(len(node.data))
+	}
+	var size int64
+	for _, child := range node.children {
+		size += m.sizeOf(child)
+	}
+	return size
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+func (m *Memory) MkdirAll(ctx context.Context, dirname string, perm os.FileMode) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: create directory recursively").
+		String("dirname", dirname).
+		Uint32("perm", uint32(perm)).Fire()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node := m.root
+	for _, elem := range m.splitPath(dirname) {
+		child := node.children[elem]
+		if child == nil {
+			child = newMemDir(perm)
+			node.children[elem] = child
+		}
+		if !child.isDir {
+			err := &os.PathError{Op: "mkdir", Path: dirname, Err: syscall.ENOTDIR}
+			lg.Error().Msg("memory: create directory recursively failed").Error("error", err).Fire()
+			return err
+		}
+		node = child
+	}
+	return nil
+}
+
+func (m *Memory) IsExists(ctx context.Context, name string) (bool, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: check file is exists").String("name", name).Fire()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lookup(m.splitPath(name)) != nil, nil
+}
+
+func (m *Memory) Stat(ctx context.Context, name string) (*FileInfo, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: stat file").String("name", name).Fire()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node := m.lookup(m.splitPath(name))
+	if node == nil {
+		err := &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+		lg.Warn().Msg("memory: stat file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	return m.fileInfo(name, node), nil
+}
+
+func (m *Memory) fileInfo(name string, node *memNode) *FileInfo {
+	fi := &FileInfo{
+		Name:    name,
+		Size:    int64(len(node.data)),
+		ModTime: node.modTime,
+		IsDir:   node.isDir,
+		ETag:    node.eTag,
+		Extra: map[string]string{
+			"permission": node.perm.String(),
+		},
+	}
+	return fi
+}
+
+func (m *Memory) List(ctx context.Context, dir string) ([]*FileInfo, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: list directory").String("dir", dir).Fire()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node := m.lookup(m.splitPath(dir))
+	if node == nil {
+		err := &os.PathError{Op: "readdir", Path: dir, Err: os.ErrNotExist}
+		lg.Error().Msg("memory: list directory failed").Error("error", err).Fire()
+		return nil, err
+	}
+	if !node.isDir {
+		err := &os.PathError{Op: "readdir", Path: dir, Err: syscall.ENOTDIR}
+		lg.Error().Msg("memory: list directory failed").Error("error", err).Fire()
+		return nil, err
+	}
+	files := make([]*FileInfo, 0, len(node.children))
+	for name, child := range node.children {
+		files = append(files, m.fileInfo(path.Join(dir, name), child))
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+func (m *Memory) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: walk directory").String("root", root).Fire()
+	return walk(ctx, m, root, fn)
+}
+
+func (m *Memory) CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (string, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: create new file for write").String("name", name).Fire()
+
+	elems := m.splitPath(name)
+	if len(elems) == 0 {
+		return "", &os.PathError{Op: "create", Path: name, Err: os.ErrExist}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	h := md5.New()
+	data := make([]byte, 4096)
+	for {
+		n, err := reader.Read(data)
+		if n > 0 {
+			h.Write(data[:n])
+			buf.Write(data[:n])
+			if m.capacity > 0 && m.usedBytes()+int64(buf.Len()) > m.capacity {
+				lg.Error().Msg("memory: create new file failed").Error("error", ErrStorageFull).Fire()
+				return "", ErrStorageFull
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			lg.Error().Msg("memory: read data failed").Error("error", err).Fire()
+			return "", err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	parent, err := m.lookupParent("create", name, elems)
+	if err != nil {
+		lg.Error().Msg("memory: create new file failed").Error("error", err).Fire()
+		return "", err
+	}
+	base := elems[len(elems)-1]
+	if _, ok := parent.children[base]; ok {
+		err = &os.PathError{Op: "create", Path: name, Err: os.ErrExist}
+		lg.Error().Msg("memory: create new file failed").Error("error", err).Fire()
+		return "", err
+	}
+	size := int64(buf.Len())
+	if m.capacity > 0 && m.used+size > m.capacity {
+		lg.Error().Msg("memory: create new file failed").Error("error", ErrStorageFull).Fire()
+		return "", ErrStorageFull
+	}
+	// Calculate the md5 as hex.
+	eTag := hex.EncodeToString(h.Sum(nil))
+
+	parent.children[base] = &memNode{perm: 0644, modTime: time.Now(), data: buf.Bytes(), eTag: eTag}
+	m.used += size
+	return eTag, nil
+}
+
+func (m *Memory) usedBytes() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.used
+}
+
+func (m *Memory) OpenForRead(ctx context.Context, name string) (io.ReadCloser, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: open file for read").String("name", name).Fire()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	node := m.lookup(m.splitPath(name))
+	if node == nil {
+		err := &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		lg.Error().Msg("memory: open file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	if node.isDir {
+		err := &os.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		lg.Error().Msg("memory: open file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	return &memReader{Reader: bytes.NewReader(node.data)}, nil
+}
+
+func (m *Memory) OpenRange(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: open file for range read").String("name", name).
+		Int64("offset", offset).Int64("length", length).Fire()
+
+	reader, err := m.OpenForRead(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	r := reader.(io.ReadSeeker)
+	size, _ := r.Seek(0, io.SeekEnd)
+	if offset < 0 || offset > size {
+		return nil, ErrInvalidRange
+	}
+	_, _ = r.Seek(offset, io.SeekStart)
+	return limitRange(reader, length), nil
+}
+
+func (m *Memory) Remove(ctx context.Context, name string) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: remove file").String("name", name).Fire()
+
+	err := m.remove("remove", name, false)
+	if err != nil {
+		lg.Error().Msg("memory: remove file failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (m *Memory) RemoveAll(ctx context.Context, name string) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: remove dir and all children").String("name", name).Fire()
+
+	err := m.remove("remove", name, true)
+	if err != nil && !os.IsNotExist(err) {
+		lg.Error().Msg("memory: remove dir and all children failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (m *Memory) remove(op string, name string, recursive bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elems := m.splitPath(name)
+	if len(elems) == 0 {
+		return &os.PathError{Op: op, Path: name, Err: syscall.EPERM}
+	}
+	parent := m.lookup(elems[:len(elems)-1])
+	if parent == nil || !parent.isDir {
+		return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	base := elems[len(elems)-1]
+	node := parent.children[base]
+	if node == nil {
+		return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	if node.isDir && len(node.children) != 0 && !recursive {
+		return &os.PathError{Op: op, Path: name, Err: syscall.ENOTEMPTY}
+	}
+	delete(parent.children, base)
+	m.used -= m.sizeOf(node)
+	return nil
+}
+
+func (m *Memory) Rename(ctx context.Context, oldName string, newName string) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: rename file").String("old", oldName).String("new", newName).Fire()
+
+	err := m.rename(oldName, newName, true)
+	if err != nil {
+		lg.Error().Msg("memory: rename file failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+func (m *Memory) RenameNoReplace(ctx context.Context, oldName string, newName string) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("memory: rename file without replace").String("old", oldName).String("new", newName).Fire()
+
+	err := m.rename(oldName, newName, false)
+	if err != nil {
+		lg.Error().Msg("memory: rename file failed").Error("error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+// rename moves oldName to newName, the existing newName is replaced only if replace is true.
+func (m *Memory) rename(oldName string, newName string, replace bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	oldElems := m.splitPath(oldName)
+	newElems := m.splitPath(newName)
+	if len(oldElems) == 0 || len(newElems) == 0 {
+		return &os.PathError{Op: "rename", Path: oldName, Err: syscall.EPERM}
+	}
+	oldParent := m.lookup(oldElems[:len(oldElems)-1])
+	if oldParent == nil || !oldParent.isDir || oldParent.children[oldElems[len(oldElems)-1]] == nil {
+		return &os.PathError{Op: "rename", Path: oldName, Err: os.ErrNotExist}
+	}
+	node := oldParent.children[oldElems[len(oldElems)-1]]
+
+	// Cannot move a directory into itself.
+	if node.isDir && len(newElems) > len(oldElems) && path.Join(newElems[:len(oldElems)]...) == path.Join(oldElems...) {
+		return &os.PathError{Op: "rename", Path: newName, Err: syscall.EINVAL}
+	}
+
+	newParent, err := m.lookupParent("rename", newName, newElems)
+	if err != nil {
+		return err
+	}
+	// Same as HDFS, overwrite the destination if it is a file or empty directory.
+	newBase := newElems[len(newElems)-1]
+	if dst := newParent.children[newBase]; dst != nil {
+		if dst == node {
+			return nil
+		}
+		if !replace {
+			return &os.PathError{Op: "rename", Path: newName, Err: os.ErrExist}
+		}
+		if dst.isDir && len(dst.children) != 0 {
+			return &os.PathError{Op: "rename", Path: newName, Err: syscall.ENOTEMPTY}
+		}
+		m.used -= m.sizeOf(dst)
+	}
+	delete(oldParent.children, oldElems[len(oldElems)-1])
+	newParent.children[newBase] = node
+	return nil
+}
@@ -0,0 +1,255 @@
+package fileio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DataWorkbench/glog"
+)
+
+// testContext returns a context with the logger that only exports the fatal records.
+func testContext() context.Context {
+	return glog.WithContext(context.Background(), glog.NewDefault().WithLevel(glog.FatalLevel))
+}
+
+// writeString creates the file name with data in fs.
+func writeString(t *testing.T, fs FileIO, name string, data string) string {
+	t.Helper()
+	eTag, err := fs.CreateAndWrite(testContext(), name, io.NopCloser(strings.NewReader(data)))
+	if err != nil {
+		t.Fatalf("create %s: %v", name, err)
+	}
+	return eTag
+}
+
+// readString returns all data of the file name in fs.
+func readString(t *testing.T, fs FileIO, name string) string {
+	t.Helper()
+	reader, err := fs.OpenForRead(testContext(), name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(data)
+}
+
+func newTestMemory(t *testing.T, capacity int64) *Memory {
+	t.Helper()
+	m, err := NewMemory(testContext(), capacity)
+	if err != nil {
+		t.Fatalf("new memory: %v", err)
+	}
+	if err = m.MkdirAll(testContext(), "/dir", 0777); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	return m
+}
+
+func TestNewMemoryNegativeCapacity(t *testing.T) {
+	if _, err := NewMemory(testContext(), -1); err == nil {
+		t.Fatal("expected error for negative capacity")
+	}
+}
+
+func TestMemoryCreateAndWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int64
+		existing map[string]string
+		file     string
+		data     string
+		eTag     string
+		check    func(error) bool
+	}{
+		{
+			name: "write",
+			file: "/dir/a",
+			data: "hello",
+			eTag: "5d41402abc4b2a76b9719d911017c592",
+		},
+		{
+			name:     "exists",
+			existing: map[string]string{"/dir/a": "old"},
+			file:     "/dir/a",
+			data:     "new",
+			check:    os.IsExist,
+		},
+		{
+			name:  "parent not exists",
+			file:  "/none/a",
+			data:  "hello",
+			check: os.IsNotExist,
+		},
+		{
+			name:     "full",
+			capacity: 8,
+			existing: map[string]string{"/dir/a": "1234"},
+			file:     "/dir/b",
+			data:     "56789",
+			check:    func(err error) bool { return errors.Is(err, ErrStorageFull) },
+		},
+		{
+			name:     "fit capacity",
+			capacity: 8,
+			existing: map[string]string{"/dir/a": "1234"},
+			file:     "/dir/b",
+			data:     "5678",
+			eTag:     "674f3c2c1a8a6f90461e8a66fb5550ba",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMemory(t, tt.capacity)
+			for name, data := range tt.existing {
+				writeString(t, m, name, data)
+			}
+			eTag, err := m.CreateAndWrite(testContext(), tt.file, io.NopCloser(strings.NewReader(tt.data)))
+			if tt.check != nil {
+				if err == nil || !tt.check(err) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if data, ok := tt.existing[tt.file]; ok && readString(t, m, tt.file) != data {
+					t.Fatal("the existing file is overwritten")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			if eTag != tt.eTag {
+				t.Fatalf("eTag = %s, want %s", eTag, tt.eTag)
+			}
+			if got := readString(t, m, tt.file); got != tt.data {
+				t.Fatalf("data = %q, want %q", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestMemoryRemoveReleasesCapacity(t *testing.T) {
+	ctx := testContext()
+	m := newTestMemory(t, 4)
+	writeString(t, m, "/dir/a", "1234")
+	if err := m.Remove(ctx, "/dir/a"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := m.Remove(ctx, "/dir/a"); !os.IsNotExist(err) {
+		t.Fatalf("remove again: %v", err)
+	}
+	writeString(t, m, "/dir/b", "5678")
+
+	if err := m.Remove(ctx, "/dir"); err == nil {
+		t.Fatal("expected error to remove a non-empty directory")
+	}
+	if err := m.RemoveAll(ctx, "/dir"); err != nil {
+		t.Fatalf("remove all: %v", err)
+	}
+	if err := m.RemoveAll(ctx, "/dir"); err != nil {
+		t.Fatalf("remove all again: %v", err)
+	}
+	if m.usedBytes() != 0 {
+		t.Fatalf("used = %d, want 0", m.usedBytes())
+	}
+}
+
+func TestMemoryStatAndList(t *testing.T) {
+	ctx := testContext()
+	m := newTestMemory(t, 0)
+	eTag := writeString(t, m, "/dir/b", "hello")
+	writeString(t, m, "/dir/a", "hi")
+	if err := m.MkdirAll(ctx, "/dir/c/d", 0777); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		size  int64
+		isDir bool
+		eTag  string
+	}{
+		{name: "/dir", isDir: true},
+		{name: "/dir/b", size: 5, eTag: eTag},
+		{name: "/dir/c/d", isDir: true},
+	}
+	for _, tt := range tests {
+		info, err := m.Stat(ctx, tt.name)
+		if err != nil {
+			t.Fatalf("stat %s: %v", tt.name, err)
+		}
+		if info.Name != tt.name || info.Size != tt.size || info.IsDir != tt.isDir || info.ETag != tt.eTag {
+			t.Fatalf("stat %s = %+v", tt.name, info)
+		}
+	}
+	if _, err := m.Stat(ctx, "/dir/none"); !os.IsNotExist(err) {
+		t.Fatalf("stat not exists: %v", err)
+	}
+	if ok, err := m.IsExists(ctx, "/dir/none"); err != nil || ok {
+		t.Fatalf("is exists = %v, %v", ok, err)
+	}
+
+	files, err := m.List(ctx, "/dir")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != "/dir/a,/dir/b,/dir/c" {
+		t.Fatalf("list = %s", got)
+	}
+	if _, err = m.List(ctx, "/dir/a"); err == nil {
+		t.Fatal("expected error to list a file")
+	}
+}
+
+func TestMemoryOpenRange(t *testing.T) {
+	m := newTestMemory(t, 0)
+	writeString(t, m, "/dir/a", "0123456789")
+
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   string
+		err    error
+	}{
+		{name: "all", offset: 0, length: 0, want: "0123456789"},
+		{name: "middle", offset: 2, length: 3, want: "234"},
+		{name: "to end", offset: 7, length: -1, want: "789"},
+		{name: "beyond length", offset: 8, length: 10, want: "89"},
+		{name: "at end", offset: 10, length: 1, want: ""},
+		{name: "beyond end", offset: 11, length: 1, err: ErrInvalidRange},
+		{name: "negative", offset: -1, length: 1, err: ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := m.OpenRange(testContext(), "/dir/a", tt.offset, tt.length)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("open range: %v", err)
+			}
+			defer func() { _ = reader.Close() }()
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Fatalf("data = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
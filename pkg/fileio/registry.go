@@ -0,0 +1,207 @@
+package fileio
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds several named FileIO backends and routes the keys (eg: space id) to them.
+// A key is routed to the backend in explicit mapping, or the default backend otherwise.
+// A backend can be set to fall back to another one for reads, see Fallback, and
+// can have a read cache that is only used by RouteForRead, see Cache.
+// It is safe for concurrent use, the routes can be changed at runtime.
+type Registry struct {
+	mu          sync.RWMutex
+	backends    map[string]FileIO
+	caches      map[string]FileIO
+	fallbacks   map[string]string
+	routes      map[string]string
+	defaultName string
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		backends:  make(map[string]FileIO),
+		caches:    make(map[string]FileIO),
+		fallbacks: make(map[string]string),
+		routes:    make(map[string]string),
+	}
+}
+
+// Register adds the backend with name. The first registered backend is the default until SetRoutes called.
+func (r *Registry) Register(name string, fs FileIO) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.backends[name]; ok {
+		return fmt.Errorf("fileio: storage <%s> already registered", name)
+	}
+	r.backends[name] = fs
+	if r.defaultName == "" {
+		r.defaultName = name
+	}
+	return nil
+}
+
+// SetRoutes replaces the routing rules. All backend names must be registered.
+func (r *Registry) SetRoutes(defaultName string, routes map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.backends[defaultName]; !ok {
+		return fmt.Errorf("fileio: default storage <%s> not registered", defaultName)
+	}
+	m := make(map[string]string, len(routes))
+	for key, name := range routes {
+		if _, ok := r.backends[name]; !ok {
+			return fmt.Errorf("fileio: storage <%s> of <%s> not registered", name, key)
+		}
+		m[key] = name
+	}
+	r.defaultName = defaultName
+	r.routes = m
+	return nil
+}
+
+// SetRoute routes the key to the named backend, removes the explicit mapping if name is empty.
+func (r *Registry) SetRoute(key, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if name == "" {
+		delete(r.routes, key)
+		return nil
+	}
+	if _, ok := r.backends[name]; !ok {
+		return fmt.Errorf("fileio: storage <%s> not registered", name)
+	}
+	r.routes[key] = name
+	return nil
+}
+
+// Reroute routes all keys that routed to backend from to backend to, includes the keys not in mapping.
+func (r *Registry) Reroute(from, to string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.backends[to]; !ok {
+		return fmt.Errorf("fileio: storage <%s> not registered", to)
+	}
+	if r.defaultName == from {
+		r.defaultName = to
+	}
+	for key, name := range r.routes {
+		if name == from {
+			r.routes[key] = to
+		}
+	}
+	return nil
+}
+
+// SetFallback makes the reads of the named backend fall back to another backend, removes it if fallback is empty.
+func (r *Registry) SetFallback(name, fallback string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.backends[name]; !ok {
+		return fmt.Errorf("fileio: storage <%s> not registered", name)
+	}
+	if fallback == "" {
+		delete(r.fallbacks, name)
+		return nil
+	}
+	if _, ok := r.backends[fallback]; !ok {
+		return fmt.Errorf("fileio: fallback storage <%s> not registered", fallback)
+	}
+	if fallback == name {
+		return fmt.Errorf("fileio: storage <%s> can not fall back to itself", name)
+	}
+	r.fallbacks[name] = fallback
+	return nil
+}
+
+// SetReadCache sets the cache of the named backend that used by RouteForRead.
+// The cache must wrap the backend, it is not closed by Registry.
+func (r *Registry) SetReadCache(name string, cache FileIO) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.backends[name]; !ok {
+		return fmt.Errorf("fileio: storage <%s> not registered", name)
+	}
+	r.caches[name] = cache
+	return nil
+}
+
+// Get returns the backend by name. The fallback is not applied.
+func (r *Registry) Get(name string) (FileIO, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fs, ok := r.backends[name]
+	return fs, ok
+}
+
+// Route returns the name and backend that the key routed to. The backend
+// is wrapped with Fallback if the fallback is set.
+func (r *Registry) Route(key string) (string, FileIO) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name := r.routeName(key)
+	return name, r.resolve(name, false)
+}
+
+// RouteForRead is similar to Route, but the backends are replaced by their read cache if set.
+// It is used to read the immutable files that hot.
+func (r *Registry) RouteForRead(key string) (string, FileIO) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name := r.routeName(key)
+	return name, r.resolve(name, true)
+}
+
+func (r *Registry) routeName(key string) string {
+	if name, ok := r.routes[key]; ok {
+		return name
+	}
+	return r.defaultName
+}
+
+func (r *Registry) resolve(name string, cached bool) FileIO {
+	get := func(name string) FileIO {
+		if cache, ok := r.caches[name]; ok && cached {
+			return cache
+		}
+		return r.backends[name]
+	}
+	if fallback, ok := r.fallbacks[name]; ok {
+		return NewFallback(get(name), get(fallback))
+	}
+	return get(name)
+}
+
+// Default returns the name and backend that the keys not in mapping routed to.
+func (r *Registry) Default() (string, FileIO) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.defaultName, r.backends[r.defaultName]
+}
+
+// Names returns the names of all backends in order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.backends))
+	for name := range r.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close closes all backends and returns the first error.
+func (r *Registry) Close() (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, fs := range r.backends {
+		if cerr := fs.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return
+}
@@ -0,0 +1,174 @@
+package fileio
+
+import (
+	"strings"
+	"testing"
+)
+
+// newTestRegistry returns a registry with the memory backends a, b and c, the a is the default.
+func newTestRegistry(t *testing.T) (*Registry, map[string]FileIO) {
+	t.Helper()
+	r := NewRegistry()
+	backends := make(map[string]FileIO)
+	for _, name := range []string{"a", "b", "c"} {
+		backends[name] = newTestMemory(t, 0)
+		if err := r.Register(name, backends[name]); err != nil {
+			t.Fatalf("register %s: %v", name, err)
+		}
+	}
+	return r, backends
+}
+
+func TestRegistryRegister(t *testing.T) {
+	r, backends := newTestRegistry(t)
+	if err := r.Register("a", newTestMemory(t, 0)); err == nil {
+		t.Fatal("expected error to register the same name")
+	}
+	if got := strings.Join(r.Names(), ","); got != "a,b,c" {
+		t.Fatalf("names = %s", got)
+	}
+	if name, fs := r.Default(); name != "a" || fs != backends["a"] {
+		t.Fatalf("default = %s", name)
+	}
+	if fs, ok := r.Get("b"); !ok || fs != backends["b"] {
+		t.Fatal("get b failed")
+	}
+	if _, ok := r.Get("none"); ok {
+		t.Fatal("get not registered")
+	}
+}
+
+func TestRegistryRoute(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(r *Registry) error
+		failed bool
+		routes map[string]string
+	}{
+		{
+			name:   "default",
+			setup:  func(r *Registry) error { return nil },
+			routes: map[string]string{"k1": "a", "k2": "a"},
+		},
+		{
+			name:   "routes",
+			setup:  func(r *Registry) error { return r.SetRoutes("b", map[string]string{"k1": "c"}) },
+			routes: map[string]string{"k1": "c", "k2": "b"},
+		},
+		{
+			name:   "default not registered",
+			setup:  func(r *Registry) error { return r.SetRoutes("none", nil) },
+			failed: true,
+			routes: map[string]string{"k1": "a"},
+		},
+		{
+			name:   "route not registered",
+			setup:  func(r *Registry) error { return r.SetRoutes("b", map[string]string{"k1": "none"}) },
+			failed: true,
+			routes: map[string]string{"k1": "a"},
+		},
+		{
+			name:   "set route",
+			setup:  func(r *Registry) error { return r.SetRoute("k1", "b") },
+			routes: map[string]string{"k1": "b", "k2": "a"},
+		},
+		{
+			name:   "set route not registered",
+			setup:  func(r *Registry) error { return r.SetRoute("k1", "none") },
+			failed: true,
+			routes: map[string]string{"k1": "a"},
+		},
+		{
+			name: "remove route",
+			setup: func(r *Registry) error {
+				if err := r.SetRoute("k1", "b"); err != nil {
+					return err
+				}
+				return r.SetRoute("k1", "")
+			},
+			routes: map[string]string{"k1": "a"},
+		},
+		{
+			name: "reroute",
+			setup: func(r *Registry) error {
+				if err := r.SetRoutes("a", map[string]string{"k1": "a", "k2": "b"}); err != nil {
+					return err
+				}
+				return r.Reroute("a", "c")
+			},
+			routes: map[string]string{"k1": "c", "k2": "b", "k3": "c"},
+		},
+		{
+			name:   "reroute not registered",
+			setup:  func(r *Registry) error { return r.Reroute("a", "none") },
+			failed: true,
+			routes: map[string]string{"k1": "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, backends := newTestRegistry(t)
+			if err := tt.setup(r); (err != nil) != tt.failed {
+				t.Fatalf("setup error = %v, want failed %v", err, tt.failed)
+			}
+			for key, want := range tt.routes {
+				name, fs := r.Route(key)
+				if name != want || fs != backends[want] {
+					t.Fatalf("route %s = %s, want %s", key, name, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRegistryFallbackAndReadCache(t *testing.T) {
+	r, backends := newTestRegistry(t)
+	if err := r.SetFallback("none", "b"); err == nil {
+		t.Fatal("expected error to set fallback of not registered")
+	}
+	if err := r.SetFallback("a", "none"); err == nil {
+		t.Fatal("expected error to fall back to not registered")
+	}
+	if err := r.SetFallback("a", "a"); err == nil {
+		t.Fatal("expected error to fall back to itself")
+	}
+	if err := r.SetReadCache("none", backends["c"]); err == nil {
+		t.Fatal("expected error to set cache of not registered")
+	}
+
+	if err := r.SetFallback("a", "b"); err != nil {
+		t.Fatalf("set fallback: %v", err)
+	}
+	_, fs := r.Route("k1")
+	fallback, ok := fs.(*Fallback)
+	if !ok || fallback.primary != backends["a"] || fallback.secondary != backends["b"] {
+		t.Fatalf("route = %T, want the fallback from a to b", fs)
+	}
+
+	// The read cache is used for both primary and secondary.
+	cacheA, cacheB := newTestMemory(t, 0), newTestMemory(t, 0)
+	if err := r.SetReadCache("a", cacheA); err != nil {
+		t.Fatalf("set read cache: %v", err)
+	}
+	if err := r.SetReadCache("b", cacheB); err != nil {
+		t.Fatalf("set read cache: %v", err)
+	}
+	_, fs = r.RouteForRead("k1")
+	if fallback, ok = fs.(*Fallback); !ok || fallback.primary != cacheA || fallback.secondary != cacheB {
+		t.Fatalf("route for read = %T, want the fallback from cache a to cache b", fs)
+	}
+	// The cache is not used for write.
+	if _, fs = r.Route("k1"); fs.(*Fallback).primary != backends["a"] {
+		t.Fatal("route uses the read cache")
+	}
+
+	if err := r.SetFallback("a", ""); err != nil {
+		t.Fatalf("remove fallback: %v", err)
+	}
+	if _, fs = r.Route("k1"); fs != backends["a"] {
+		t.Fatalf("route = %T, want a", fs)
+	}
+	if _, fs = r.RouteForRead("k1"); fs != cacheA {
+		t.Fatalf("route for read = %T, want cache a", fs)
+	}
+}
@@ -2,18 +2,29 @@ package fileio
 
 import (
 	"context"
-	"errors"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path"
+	"sort"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/DataWorkbench/glog"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
 )
 
 var _ FileIO = (*S3Client)(nil)
@@ -30,10 +41,21 @@ type S3Config struct {
 	Bucket string `json:"bucket" yaml:"bucket" env:"BUCKET" validate:"required"`
 
 	// The access key id.
-	AccessKeyId string `json:"access_key_id" yaml:"access_key_id" env:"ACCESS_KEY_ID" validate:"required"`
+	AccessKeyId string `json:"access_key_id" yaml:"access_key_id" env:"ACCESS_KEY_ID" validate:"required_without=AccessKeyIdFile" secret:"true"`
 
 	// The access secret key id.
-	SecretAccessKey string `json:"secret_access_key" yaml:"secret_access_key" env:"SECRET_ACCESS_KEY" validate:"required"`
+	SecretAccessKey string `json:"secret_access_key" yaml:"secret_access_key" env:"SECRET_ACCESS_KEY" validate:"required_without=SecretAccessKeyFile" secret:"true"`
+
+	// The file that contains the access key id, such as a kubernetes secret mount.
+	// Takes precedence over AccessKeyId.
+	AccessKeyIdFile string `json:"access_key_id_file" yaml:"access_key_id_file" env:"ACCESS_KEY_ID_FILE" validate:"-"`
+
+	// The file that contains the access secret key. Takes precedence over SecretAccessKey.
+	SecretAccessKeyFile string `json:"secret_access_key_file" yaml:"secret_access_key_file" env:"SECRET_ACCESS_KEY_FILE" validate:"-"`
+
+	// The interval to read the key files again, so the rotated keys take effect without restart.
+	// Defaults to 0, means the files are read only once at startup.
+	SecretReloadInterval time.Duration `json:"secret_reload_interval" yaml:"secret_reload_interval" env:"SECRET_RELOAD_INTERVAL,default=0s" validate:"gte=0"`
 
 	// Set this to `true` to disable SSL when sending requests. Defaults
 	// to `false`.
@@ -44,17 +66,29 @@ type S3Config struct {
 	// will use virtual hosted bucket addressing when possible
 	// (`http://BUCKET.s3.amazonaws.com/KEY`).
 	ForcePathStyle bool `json:"force_path_style" yaml:"force_path_style" env:"FORCE_PATH_STYLE" validate:"-"`
+
+	// The size in MiB of each part in multipart upload. Defaults to 16, the minimum is 5.
+	UploadPartSize int64 `json:"upload_part_size" yaml:"upload_part_size" env:"UPLOAD_PART_SIZE,default=16" validate:"gte=5"`
+
+	// The number of parts to upload in parallel for each file. Defaults to 4.
+	UploadConcurrency int `json:"upload_concurrency" yaml:"upload_concurrency" env:"UPLOAD_CONCURRENCY,default=4" validate:"gte=1"`
 }
 
 type S3Client struct {
-	svc    *s3.S3
-	bucket *string
+	svc      s3iface.S3API
+	bucket   *string
+	endpoint string
+	uploader *s3manager.Uploader
 }
 
 func NewS3Client(ctx context.Context, cfg *S3Config) (FileIO, error) {
+	creds, err := newS3Credentials(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
 	sess, err := session.NewSession(&aws.Config{
 		CredentialsChainVerboseErrors: nil,
-		Credentials:                   credentials.NewStaticCredentials(cfg.AccessKeyId, cfg.SecretAccessKey, ""),
+		Credentials:                   creds,
 		Endpoint:                      aws.String(cfg.Endpoint),
 		Region:                        aws.String(cfg.Region),
 		DisableSSL:                    aws.Bool(cfg.DisableSSL),
@@ -70,9 +104,27 @@ func NewS3Client(ctx context.Context, cfg *S3Config) (FileIO, error) {
 		return nil, err
 	}
 
+	svc := s3.New(sess)
+	svc.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "fileio.TraceRequestId",
+		Fn:   traceS3RequestId,
+	})
+	uploader := s3manager.NewUploaderWithClient(svc, func(u *s3manager.Uploader) {
+		if cfg.UploadPartSize > 0 {
+			u.PartSize = cfg.UploadPartSize * 1024 * 1024
+		}
+		if cfg.UploadConcurrency > 0 {
+			u.Concurrency = cfg.UploadConcurrency
+		}
+		// Abort the multipart upload and remove the uploaded parts if any error occurs.
+		u.LeavePartsOnError = false
+	})
+
 	cli := &S3Client{
-		svc:    s3.New(sess),
-		bucket: aws.String(cfg.Bucket),
+		svc:      svc,
+		bucket:   aws.String(cfg.Bucket),
+		endpoint: cfg.Endpoint,
+		uploader: uploader,
 	}
 	return cli, nil
 }
@@ -84,6 +136,35 @@ func (cli *S3Client) Close() error {
 	return nil
 }
 
+// SpanTags returns the tags to set on the spans of Traced.
+func (cli *S3Client) SpanTags() opentracing.Tags {
+	return opentracing.Tags{
+		"s3.endpoint": cli.endpoint,
+		"s3.bucket":   aws.StringValue(cli.bucket),
+	}
+}
+
+// traceS3RequestId logs the request id of each s3 request to the span in the request context.
+// An operation may send multiple requests, such as the multipart upload.
+func traceS3RequestId(r *request.Request) {
+	span := opentracing.SpanFromContext(r.Context())
+	if span == nil {
+		return
+	}
+	span.SetTag("s3.request_id", r.RequestID)
+	span.LogFields(
+		log.String("s3.operation", r.Operation.Name),
+		log.String("s3.request_id", r.RequestID),
+		log.Int("s3.retries", r.RetryCount),
+	)
+}
+
+// Ping checks the bucket is reachable by HeadBucket.
+func (cli *S3Client) Ping(ctx context.Context) error {
+	_, err := cli.svc.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: cli.bucket})
+	return err
+}
+
 func (cli *S3Client) MkdirAll(ctx context.Context, dirname string, perm os.FileMode) error {
 	// TODO: we need create `directory` in object storage?
 	return nil
@@ -106,26 +187,173 @@ func (cli *S3Client) IsExists(ctx context.Context, name string) (bool, error) {
 	return true, nil
 }
 
+func (cli *S3Client) Stat(ctx context.Context, name string) (*FileInfo, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("s3: stat file").String("name", name).Fire()
+	output, err := cli.svc.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: cli.bucket,
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NotFound" {
+			// The `directory` in object storage is only the common prefix of keys.
+			return cli.statDir(ctx, name)
+		}
+		lg.Warn().Msg("s3: stat file failed").Error("error", err).Fire()
+		return nil, err
+	}
+
+	fi := &FileInfo{
+		Name:    name,
+		Size:    aws.Int64Value(output.ContentLength),
+		ModTime: aws.TimeValue(output.LastModified),
+		IsDir:   false,
+		ETag:    strings.Trim(aws.StringValue(output.ETag), "\""),
+		Extra:   make(map[string]string),
+	}
+	if output.ContentType != nil {
+		fi.Extra["content_type"] = *output.ContentType
+	}
+	if output.StorageClass != nil {
+		fi.Extra["storage_class"] = *output.StorageClass
+	}
+	if output.VersionId != nil {
+		fi.Extra["version_id"] = *output.VersionId
+	}
+	for k, v := range output.Metadata {
+		fi.Extra["meta_"+strings.ToLower(k)] = aws.StringValue(v)
+	}
+	return fi, nil
+}
+
+// statDir check whether the name is the common prefix of any object.
+func (cli *S3Client) statDir(ctx context.Context, name string) (*FileInfo, error) {
+	lg := glog.FromContext(ctx)
+	prefix := strings.TrimSuffix(strings.TrimPrefix(name, "/"), "/") + "/"
+	output, err := cli.svc.ListObjectsV2WithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket:  cli.bucket,
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int64(1),
+	})
+	if err != nil {
+		lg.Warn().Msg("s3: stat file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	if aws.Int64Value(output.KeyCount) == 0 {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return &FileInfo{Name: name, IsDir: true}, nil
+}
+
+func (cli *S3Client) List(ctx context.Context, dir string) ([]*FileInfo, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("s3: list directory").String("dir", dir).Fire()
+
+	prefix := strings.TrimSuffix(strings.TrimPrefix(dir, "/"), "/") + "/"
+	if prefix == "/" {
+		prefix = ""
+	}
+	input := &s3.ListObjectsV2Input{
+		Bucket:    cli.bucket,
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}
+
+	var files []*FileInfo
+	err := cli.svc.ListObjectsV2PagesWithContext(ctx, input, func(output *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, p := range output.CommonPrefixes {
+			name := strings.TrimSuffix(strings.TrimPrefix(aws.StringValue(p.Prefix), prefix), "/")
+			files = append(files, &FileInfo{Name: path.Join(dir, name), IsDir: true})
+		}
+		for _, obj := range output.Contents {
+			name := strings.TrimPrefix(aws.StringValue(obj.Key), prefix)
+			if name == "" {
+				// The placeholder object of directory.
+				continue
+			}
+			files = append(files, s3ObjectInfo(path.Join(dir, name), obj))
+		}
+		return true
+	})
+	if err != nil {
+		lg.Error().Msg("s3: list directory failed").Error("error", err).Fire()
+		return nil, err
+	}
+	if len(files) == 0 {
+		if exists, err := cli.IsExists(ctx, dir); err != nil || exists {
+			// The dir is a file or the stat failed.
+			if err == nil {
+				err = &os.PathError{Op: "readdir", Path: dir, Err: syscall.ENOTDIR}
+			}
+			lg.Error().Msg("s3: list directory failed").Error("error", err).Fire()
+			return nil, err
+		}
+		err = &os.PathError{Op: "readdir", Path: dir, Err: os.ErrNotExist}
+		lg.Error().Msg("s3: list directory failed").Error("error", err).Fire()
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+	return files, nil
+}
+
+func s3ObjectInfo(name string, obj *s3.Object) *FileInfo {
+	fi := &FileInfo{
+		Name:    name,
+		Size:    aws.Int64Value(obj.Size),
+		ModTime: aws.TimeValue(obj.LastModified),
+		IsDir:   false,
+		ETag:    strings.Trim(aws.StringValue(obj.ETag), "\""),
+		Extra:   make(map[string]string),
+	}
+	if obj.StorageClass != nil {
+		fi.Extra["storage_class"] = *obj.StorageClass
+	}
+	return fi
+}
+
+func (cli *S3Client) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("s3: walk directory").String("root", root).Fire()
+	return walk(ctx, cli, root, fn)
+}
+
 func (cli *S3Client) CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (string, error) {
 	lg := glog.FromContext(ctx)
 	lg.Debug().Msg("s3: create new file for write").String("name", name).Fire()
 
-	output, err := cli.svc.PutObjectWithContext(ctx, &s3.PutObjectInput{
-		//ContentLength: aws.Int64(int64(len(b1) * 3)),
+	// Same as HDFS, the file must not exist. The object storage has no conditional put, so
+	// the check is best-effort and a concurrent writer to the same name may still be overwritten.
+	if _, err := cli.Stat(ctx, name); err == nil {
+		err = &os.PathError{Op: "create", Path: name, Err: os.ErrExist}
+		lg.Error().Msg("s3: create new file failed").Error("error", err).Fire()
+		return "", err
+	} else if !os.IsNotExist(err) {
+		lg.Error().Msg("s3: create new file failed").Error("error", err).Fire()
+		return "", err
+	}
+
+	// The ETag returned by s3 is not the md5 of data in multipart upload,
+	// so calculate it by self to keep same as HDFS.
+	h := md5.New()
+	output, err := cli.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
 		Bucket: cli.bucket,
 		Key:    aws.String(name),
-		Body:   aws.ReadSeekCloser(reader),
+		Body:   io.TeeReader(reader, h),
 	})
 	if err != nil {
+		if merr, ok := err.(s3manager.MultiUploadFailure); ok {
+			lg.Error().Msg("s3: multipart upload failed and aborted").String("uploadId", merr.UploadID()).Fire()
+		}
 		lg.Error().Msg("s3: create new file failed").Error("error", err).Fire()
 		return "", err
 	}
-	if output.ETag == nil {
-		return "", errors.New("no ETag in s3 response")
-	}
+	lg.Debug().Msg("s3: create new file done").String("location", output.Location).
+		String("uploadId", output.UploadID).Fire()
 
-	eTag := *output.ETag
-	eTag = eTag[1 : len(eTag)-1]
+	// Calculate the md5 as hex.
+	eTag := hex.EncodeToString(h.Sum(nil))
 	return eTag, nil
 }
 
@@ -137,12 +365,57 @@ func (cli *S3Client) OpenForRead(ctx context.Context, name string) (io.ReadClose
 		Key:    aws.String(name),
 	})
 	if err != nil {
+		err = s3NotExist("open", name, err)
 		lg.Error().Msg("s3: open file failed").Error("error", err).Fire()
 		return nil, err
 	}
 	return output.Body, nil
 }
 
+func (cli *S3Client) OpenRange(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	lg := glog.FromContext(ctx)
+	lg.Debug().Msg("s3: open file for range read").String("name", name).
+		Int64("offset", offset).Int64("length", length).Fire()
+	if offset < 0 {
+		return nil, ErrInvalidRange
+	}
+
+	// The range in http header is inclusive.
+	var byteRange string
+	if length > 0 {
+		byteRange = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	} else {
+		byteRange = fmt.Sprintf("bytes=%d-", offset)
+	}
+	output, err := cli.svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: cli.bucket,
+		Key:    aws.String(name),
+		Range:  aws.String(byteRange),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidRange" {
+			// S3 rejects the range that starts at the end of object. Keep same as other storage.
+			if info, statErr := cli.Stat(ctx, name); statErr == nil && info.Size == offset {
+				return io.NopCloser(strings.NewReader("")), nil
+			}
+			return nil, ErrInvalidRange
+		}
+		err = s3NotExist("open", name, err)
+		lg.Error().Msg("s3: open file failed").Error("error", err).Fire()
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+// s3NotExist converts the error of missing object to the error that satisfies os.IsNotExist,
+// so the callers can check it same as other storage. Other errors are returned unchanged.
+func s3NotExist(op string, name string, err error) error {
+	if aerr, ok := err.(awserr.Error); ok && (aerr.Code() == s3.ErrCodeNoSuchKey || aerr.Code() == "NotFound") {
+		return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	return err
+}
+
 func (cli *S3Client) Remove(ctx context.Context, name string) error {
 	lg := glog.FromContext(ctx)
 	lg.Debug().Msg("s3: remove file").String("name", name).Fire()
@@ -182,17 +455,180 @@ func (cli *S3Client) Rename(ctx context.Context, oldName string, newName string)
 	lg := glog.FromContext(ctx)
 	lg.Debug().Msg("s3: rename file").String("old", oldName).String("new", newName).Fire()
 
+	info, err := cli.Stat(ctx, oldName)
+	if err != nil {
+		lg.Error().Msg("s3: rename file failed").Error("error", err).Fire()
+		return err
+	}
+	if !info.IsDir {
+		if err = cli.copyObject(ctx, oldName, newName, info.Size); err != nil {
+			lg.Error().Msg("s3: rename file failed").Error("error", err).Fire()
+			return err
+		}
+		return cli.Remove(ctx, oldName)
+	}
+
+	// The object storage has no directory, so copy all objects with the prefix one by one.
+	oldPrefix := strings.TrimSuffix(strings.TrimPrefix(oldName, "/"), "/") + "/"
+	newPrefix := strings.TrimSuffix(strings.TrimPrefix(newName, "/"), "/") + "/"
+	input := &s3.ListObjectsV2Input{
+		Bucket: cli.bucket,
+		Prefix: aws.String(oldPrefix),
+	}
+	var copyErr error
+	err = cli.svc.ListObjectsV2PagesWithContext(ctx, input, func(output *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range output.Contents {
+			key := aws.StringValue(obj.Key)
+			if copyErr = cli.copyObject(ctx, key, newPrefix+strings.TrimPrefix(key, oldPrefix), aws.Int64Value(obj.Size)); copyErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err == nil {
+		err = copyErr
+	}
+	if err != nil {
+		lg.Error().Msg("s3: rename directory failed").Error("error", err).Fire()
+		return err
+	}
+	return cli.RemoveAll(ctx, oldPrefix)
+}
+
+// maxCopyObjectSize is the max size of object that can be copied by a single CopyObject.
+const maxCopyObjectSize = 5 * 1024 * 1024 * 1024
+
+// copyPartSize is the default size of each part in multipart copy.
+const copyPartSize = 512 * 1024 * 1024
+
+// copyObject copies the object in the same bucket. The object larger than 5 GiB is copied
+// by multipart copy, because it is rejected by CopyObject.
+func (cli *S3Client) copyObject(ctx context.Context, src string, dst string, size int64) error {
+	if size > maxCopyObjectSize {
+		return cli.copyObjectMultipart(ctx, src, dst, size)
+	}
 	_, err := cli.svc.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
 		Bucket:     cli.bucket,
-		CopySource: aws.String(oldName),
-		Key:        aws.String(newName),
+		CopySource: cli.copySource(src),
+		Key:        aws.String(dst),
+	})
+	return err
+}
+
+// copySource returns the copy source of the object. It must be URL-encoded and contains the bucket name.
+func (cli *S3Client) copySource(name string) *string {
+	source := &url.URL{Path: aws.StringValue(cli.bucket) + "/" + strings.TrimPrefix(name, "/")}
+	return aws.String(source.EscapedPath())
+}
+
+// copyPartRanges splits the object of size into the byte ranges of UploadPartCopy. The ranges
+// are no more than s3manager.MaxUploadParts.
+func copyPartRanges(size int64) []string {
+	partSize := int64(copyPartSize)
+	if n := (size + s3manager.MaxUploadParts - 1) / s3manager.MaxUploadParts; n > partSize {
+		partSize = n
+	}
+	var ranges []string
+	for start := int64(0); start < size; start += partSize {
+		end := start + partSize - 1
+		if end >= size {
+			end = size - 1
+		}
+		ranges = append(ranges, fmt.Sprintf("bytes=%d-%d", start, end))
+	}
+	return ranges
+}
+
+// copyObjectMultipart copies the object by UploadPartCopy, the parts are copied in parallel by
+// the upload concurrency. The multipart upload is aborted if any error occurs.
+func (cli *S3Client) copyObjectMultipart(ctx context.Context, src string, dst string, size int64) (err error) {
+	lg := glog.FromContext(ctx)
+
+	// The metadata is not copied by multipart copy, so it must be specified at create.
+	head, err := cli.svc.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: cli.bucket,
+		Key:    aws.String(src),
 	})
 	if err != nil {
-		lg.Error().Msg("s3: rename file failed").Error("error", err).Fire()
 		return err
 	}
-	if err = cli.Remove(ctx, oldName); err != nil {
+	created, err := cli.svc.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      cli.bucket,
+		Key:         aws.String(dst),
+		ContentType: head.ContentType,
+		Metadata:    head.Metadata,
+	})
+	if err != nil {
 		return err
 	}
-	return nil
+	defer func() {
+		if err == nil {
+			return
+		}
+		lg.Error().Msg("s3: multipart copy failed and aborted").String("uploadId", aws.StringValue(created.UploadId)).Fire()
+		_, _ = cli.svc.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   cli.bucket,
+			Key:      aws.String(dst),
+			UploadId: created.UploadId,
+		})
+	}()
+
+	// The parts not started yet are skipped once any part failed.
+	copyCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		partErr error
+	)
+	ranges := copyPartRanges(size)
+	parts := make([]*s3.CompletedPart, len(ranges))
+	sem := make(chan struct{}, cli.uploader.Concurrency)
+	for i := range ranges {
+		sem <- struct{}{}
+		if copyCtx.Err() != nil {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			number := aws.Int64(int64(i + 1))
+			output, err := cli.svc.UploadPartCopyWithContext(copyCtx, &s3.UploadPartCopyInput{
+				Bucket:          cli.bucket,
+				Key:             aws.String(dst),
+				UploadId:        created.UploadId,
+				PartNumber:      number,
+				CopySource:      cli.copySource(src),
+				CopySourceRange: aws.String(ranges[i]),
+			})
+			if err != nil {
+				errOnce.Do(func() {
+					partErr = err
+					cancel()
+				})
+				return
+			}
+			parts[i] = &s3.CompletedPart{ETag: output.CopyPartResult.ETag, PartNumber: number}
+		}(i)
+	}
+	wg.Wait()
+	if partErr != nil {
+		return partErr
+	}
+	// The loop may stop early because ctx is done.
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err = cli.svc.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          cli.bucket,
+		Key:             aws.String(dst),
+		UploadId:        created.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	return err
 }
@@ -0,0 +1,84 @@
+package fileio
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DataWorkbench/glog"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// s3FileProviderName is the name of credentials provider that reads keys from files.
+const s3FileProviderName = "S3FileProvider"
+
+// newS3Credentials creates the credentials by the keys or key files in cfg. The key files
+// are read again at the reload interval if it is set, so the rotated keys take effect.
+func newS3Credentials(ctx context.Context, cfg *S3Config) (*credentials.Credentials, error) {
+	p := &s3FileProvider{lg: glog.FromContext(ctx), cfg: cfg}
+	value, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SecretReloadInterval <= 0 || (cfg.AccessKeyIdFile == "" && cfg.SecretAccessKeyFile == "") {
+		return credentials.NewStaticCredentialsFromCreds(value), nil
+	}
+	p.last = value
+	p.SetExpiration(time.Now().Add(cfg.SecretReloadInterval), 0)
+	return credentials.NewCredentials(p), nil
+}
+
+// s3FileProvider is a credentials.Provider that reads the keys from files when expired.
+type s3FileProvider struct {
+	credentials.Expiry
+
+	lg   *glog.Logger
+	cfg  *S3Config
+	last credentials.Value
+}
+
+// Retrieve reads the key files. The previous keys are kept if failed to read,
+// because the files may be being updated, and they will be read again at next interval.
+func (p *s3FileProvider) Retrieve() (credentials.Value, error) {
+	p.SetExpiration(time.Now().Add(p.cfg.SecretReloadInterval), 0)
+	value, err := p.read()
+	if err != nil {
+		p.lg.Warn().Msg("s3: reload key files failed, keep the previous keys").Error("error", err).Fire()
+		return p.last, nil
+	}
+	if value.AccessKeyID != p.last.AccessKeyID || value.SecretAccessKey != p.last.SecretAccessKey {
+		p.lg.Info().Msg("s3: the keys in files changed, use the new keys").Fire()
+	}
+	p.last = value
+	return value, nil
+}
+
+// read returns the keys in files, or in config if the file is not set.
+func (p *s3FileProvider) read() (value credentials.Value, err error) {
+	value.ProviderName = s3FileProviderName
+	if value.AccessKeyID, err = readSecret(p.cfg.AccessKeyIdFile, p.cfg.AccessKeyId); err != nil {
+		return
+	}
+	if value.SecretAccessKey, err = readSecret(p.cfg.SecretAccessKeyFile, p.cfg.SecretAccessKey); err != nil {
+		return
+	}
+	if value.AccessKeyID == "" || value.SecretAccessKey == "" {
+		err = fmt.Errorf("s3: access key id and secret access key must not be empty")
+	}
+	return
+}
+
+// readSecret returns the content of file with the surrounding whitespace trimmed,
+// or value if file is empty.
+func readSecret(file string, value string) (string, error) {
+	if file == "" {
+		return value, nil
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,318 @@
+package fileio
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// fakeS3 is the stub of s3 API that keeps the objects in memory. The methods not overridden panic.
+type fakeS3 struct {
+	s3iface.S3API
+
+	// failPart is the number of part that UploadPart or UploadPartCopy fails, the parts copied after
+	// it are blocked until the context done. No part fails if 0.
+	failPart int64
+
+	mu        sync.Mutex
+	objects   map[string][]byte
+	parts     map[int64][]byte // The uploaded parts of the multipart upload in progress.
+	started   []int64
+	aborted   bool
+	completed bool
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: make(map[string][]byte), parts: make(map[int64][]byte)}
+}
+
+func (f *fakeS3) object(key string) ([]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, ok := f.objects[key]
+	return data, ok
+}
+
+func (f *fakeS3) HeadObjectWithContext(ctx aws.Context, input *s3.HeadObjectInput, opts ...request.Option) (*s3.HeadObjectOutput, error) {
+	data, ok := f.object(aws.StringValue(input.Key))
+	if !ok {
+		return nil, awserr.New("NotFound", "Not Found", nil)
+	}
+	return &s3.HeadObjectOutput{
+		ContentLength: aws.Int64(int64(len(data))),
+		ContentType:   aws.String("application/octet-stream"),
+	}, nil
+}
+
+func (f *fakeS3) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	data, ok := f.object(aws.StringValue(input.Key))
+	if !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "The specified key does not exist.", nil)
+	}
+	if input.Range != nil {
+		var start, end int64
+		if n, _ := fmt.Sscanf(aws.StringValue(input.Range), "bytes=%d-%d", &start, &end); n < 2 || end >= int64(len(data)) {
+			end = int64(len(data)) - 1
+		}
+		if start >= int64(len(data)) {
+			return nil, awserr.New("InvalidRange", "The requested range is not satisfiable", nil)
+		}
+		data = data[start : end+1]
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func (f *fakeS3) ListObjectsV2WithContext(ctx aws.Context, input *s3.ListObjectsV2Input, opts ...request.Option) (*s3.ListObjectsV2Output, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var count int64
+	for key := range f.objects {
+		if strings.HasPrefix(strings.TrimPrefix(key, "/"), aws.StringValue(input.Prefix)) {
+			count++
+		}
+	}
+	return &s3.ListObjectsV2Output{KeyCount: aws.Int64(count)}, nil
+}
+
+func (f *fakeS3) PutObjectRequest(input *s3.PutObjectInput) (*request.Request, *s3.PutObjectOutput) {
+	output := &s3.PutObjectOutput{}
+	req := request.New(aws.Config{}, metadata.ClientInfo{}, request.Handlers{}, nil,
+		&request.Operation{Name: "PutObject"}, input, output)
+	req.Handlers.Send.PushBack(func(r *request.Request) {
+		data, err := io.ReadAll(input.Body)
+		if err != nil {
+			r.Error = err
+			return
+		}
+		f.mu.Lock()
+		f.objects[aws.StringValue(input.Key)] = data
+		f.mu.Unlock()
+	})
+	return req, output
+}
+
+// GetObjectRequest is used by the uploader to presign the location of uploaded object.
+func (f *fakeS3) GetObjectRequest(input *s3.GetObjectInput) (*request.Request, *s3.GetObjectOutput) {
+	output := &s3.GetObjectOutput{}
+	req := request.New(aws.Config{}, metadata.ClientInfo{Endpoint: "https://s3.test"}, request.Handlers{}, nil,
+		&request.Operation{Name: "GetObject", HTTPMethod: "GET", HTTPPath: "/"}, input, output)
+	return req, output
+}
+
+func (f *fakeS3) CreateMultipartUploadWithContext(ctx aws.Context, input *s3.CreateMultipartUploadInput, opts ...request.Option) (*s3.CreateMultipartUploadOutput, error) {
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil
+}
+
+func (f *fakeS3) UploadPartWithContext(ctx aws.Context, input *s3.UploadPartInput, opts ...request.Option) (*s3.UploadPartOutput, error) {
+	number := aws.Int64Value(input.PartNumber)
+	if number == f.failPart {
+		return nil, errors.New("part failed")
+	}
+	data, err := io.ReadAll(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.started = append(f.started, number)
+	f.parts[number] = data
+	f.mu.Unlock()
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag-%d", number))}, nil
+}
+
+func (f *fakeS3) UploadPartCopyWithContext(ctx aws.Context, input *s3.UploadPartCopyInput, opts ...request.Option) (*s3.UploadPartCopyOutput, error) {
+	number := aws.Int64Value(input.PartNumber)
+	f.mu.Lock()
+	f.started = append(f.started, number)
+	f.mu.Unlock()
+	switch {
+	case f.failPart == 0 || number < f.failPart:
+		return &s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String("etag")}}, nil
+	case number == f.failPart:
+		return nil, errors.New("part failed")
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (f *fakeS3) AbortMultipartUploadWithContext(ctx aws.Context, input *s3.AbortMultipartUploadInput, opts ...request.Option) (*s3.AbortMultipartUploadOutput, error) {
+	f.mu.Lock()
+	f.aborted = true
+	f.mu.Unlock()
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (f *fakeS3) CompleteMultipartUploadWithContext(ctx aws.Context, input *s3.CompleteMultipartUploadInput, opts ...request.Option) (*s3.CompleteMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.completed = true
+	// The copied parts have no data, only the uploaded parts are assembled.
+	if len(f.parts) != 0 {
+		var data []byte
+		for _, part := range input.MultipartUpload.Parts {
+			data = append(data, f.parts[aws.Int64Value(part.PartNumber)]...)
+		}
+		f.objects[aws.StringValue(input.Key)] = data
+	}
+	return &s3.CompleteMultipartUploadOutput{Location: aws.String("location")}, nil
+}
+
+func newTestS3Client(svc s3iface.S3API, concurrency int) *S3Client {
+	return &S3Client{
+		svc:      svc,
+		bucket:   aws.String("bucket"),
+		endpoint: "s3.test",
+		uploader: s3manager.NewUploaderWithClient(svc, func(u *s3manager.Uploader) {
+			u.Concurrency = concurrency
+			u.LeavePartsOnError = false
+		}),
+	}
+}
+
+func TestS3CreateAndWriteMultipart(t *testing.T) {
+	// 3 parts of 5 MiB.
+	data := bytes.Repeat([]byte("0123456789"), 1200*1024)
+	sum := md5.Sum(data)
+	tests := []struct {
+		name     string
+		size     int
+		failPart int64
+		parts    int
+	}{
+		{name: "single", size: 1024},
+		{name: "multipart", size: len(data), parts: 3},
+		{name: "failed", size: len(data), failPart: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newFakeS3()
+			svc.failPart = tt.failPart
+			cli := newTestS3Client(svc, 2)
+			cli.uploader.PartSize = s3manager.MinUploadPartSize
+
+			eTag, err := cli.CreateAndWrite(testContext(), "/dir/a", io.NopCloser(bytes.NewReader(data[:tt.size])))
+			if tt.failPart != 0 {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if _, ok := svc.object("/dir/a"); ok || !svc.aborted || svc.completed {
+					t.Fatalf("object is published, aborted %v, completed %v", svc.aborted, svc.completed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			got, _ := svc.object("/dir/a")
+			if !bytes.Equal(got, data[:tt.size]) || len(svc.parts) != tt.parts {
+				t.Fatalf("object of %d bytes, %d parts", len(got), len(svc.parts))
+			}
+			// The eTag is the md5 of data even if uploaded in parts.
+			want := md5.Sum(data[:tt.size])
+			if tt.size == len(data) {
+				want = sum
+			}
+			if eTag != hex.EncodeToString(want[:]) {
+				t.Fatalf("eTag = %s", eTag)
+			}
+		})
+	}
+}
+
+func TestCopyPartRanges(t *testing.T) {
+	const partSize = copyPartSize
+	tests := []struct {
+		name  string
+		size  int64
+		parts int
+		last  string
+	}{
+		{name: "one part", size: 100, parts: 1, last: "bytes=0-99"},
+		{name: "exact parts", size: 2 * partSize, parts: 2, last: fmt.Sprintf("bytes=%d-%d", partSize, 2*partSize-1)},
+		{name: "partial last", size: 2*partSize + 1, parts: 3, last: fmt.Sprintf("bytes=%d-%d", 2*partSize, 2*partSize)},
+		// The part size is enlarged to keep the number of parts under the limit.
+		{name: "max parts", size: (s3manager.MaxUploadParts + 1) * partSize, parts: s3manager.MaxUploadParts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges := copyPartRanges(tt.size)
+			if len(ranges) != tt.parts || (tt.last != "" && ranges[len(ranges)-1] != tt.last) {
+				t.Fatalf("%d ranges, last %s", len(ranges), ranges[len(ranges)-1])
+			}
+		})
+	}
+}
+
+func TestCopyObjectMultipart(t *testing.T) {
+	// 12 parts of 512 MiB.
+	const size = 6 * 1024 * 1024 * 1024
+	tests := []struct {
+		name        string
+		concurrency int
+		failPart    int64
+		maxStarted  int
+	}{
+		{name: "succeeded", concurrency: 4, maxStarted: 12},
+		{name: "failed sequentially", concurrency: 1, failPart: 2, maxStarted: 2},
+		{name: "failed in parallel", concurrency: 4, failPart: 1, maxStarted: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newFakeS3()
+			svc.failPart = tt.failPart
+			svc.objects["/a"] = nil
+			cli := newTestS3Client(svc, tt.concurrency)
+			err := cli.copyObjectMultipart(testContext(), "/a", "/b", size)
+			if tt.failPart == 0 {
+				if err != nil {
+					t.Fatalf("copy: %v", err)
+				}
+				if len(svc.started) != 12 || !svc.completed || svc.aborted {
+					t.Fatalf("started %d parts, completed %v, aborted %v", len(svc.started), svc.completed, svc.aborted)
+				}
+				return
+			}
+			if err == nil || errors.Is(err, context.Canceled) {
+				t.Fatalf("error = %v, want the error of failed part", err)
+			}
+			if len(svc.started) > tt.maxStarted {
+				t.Fatalf("started %d parts after failed, want at most %d", len(svc.started), tt.maxStarted)
+			}
+			if !svc.aborted || svc.completed {
+				t.Fatalf("aborted %v, completed %v", svc.aborted, svc.completed)
+			}
+		})
+	}
+}
+
+func TestS3CreateAndWriteNoOverwrite(t *testing.T) {
+	svc := newFakeS3()
+	cli := newTestS3Client(svc, 1)
+	ctx := testContext()
+	if _, err := cli.CreateAndWrite(ctx, "/dir/a", io.NopCloser(strings.NewReader("a"))); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for _, name := range []string{"/dir/a", "/dir"} {
+		if _, err := cli.CreateAndWrite(ctx, name, io.NopCloser(strings.NewReader("b"))); !os.IsExist(err) {
+			t.Fatalf("create %s: error = %v, want exists", name, err)
+		}
+	}
+	if data, _ := svc.object("/dir/a"); !bytes.Equal(data, []byte("a")) {
+		t.Fatalf("data = %q, want %q", data, "a")
+	}
+}
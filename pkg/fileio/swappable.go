@@ -0,0 +1,186 @@
+package fileio
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// Swappable is a FileIO whose underlying client can be replaced at runtime, such as
+// when the credentials changed. The operations started before Swap finish on the
+// previous client, which is closed after all of them finished. A reader opened from
+// the previous client keeps it alive until the reader is closed.
+type Swappable struct {
+	mu      sync.RWMutex
+	current *swappableClient
+	closed  bool
+}
+
+var _ FileIO = (*Swappable)(nil)
+
+// swappableClient counts the in-flight operations of a client.
+type swappableClient struct {
+	fs FileIO
+	wg sync.WaitGroup
+}
+
+// NewSwappable creates a Swappable that uses fs.
+func NewSwappable(fs FileIO) *Swappable {
+	return &Swappable{current: &swappableClient{fs: fs}}
+}
+
+// acquire returns the current client, must call release after the operation finished.
+func (s *Swappable) acquire() *swappableClient {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c := s.current
+	c.wg.Add(1)
+	return c
+}
+
+func (c *swappableClient) release() {
+	c.wg.Done()
+}
+
+// Swap replaces the client with fs. The returned channel receives the error of closing
+// the previous client after its in-flight operations finished.
+func (s *Swappable) Swap(fs FileIO) <-chan error {
+	s.mu.Lock()
+	prev := s.current
+	s.current = &swappableClient{fs: fs}
+	s.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		prev.wg.Wait()
+		done <- prev.fs.Close()
+	}()
+	return done
+}
+
+// Close closes the current client after its in-flight operations finished.
+func (s *Swappable) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	c := s.current
+	s.mu.Unlock()
+
+	c.wg.Wait()
+	return c.fs.Close()
+}
+
+// SpanTags returns the tags of current client, see Traced.
+func (s *Swappable) SpanTags() opentracing.Tags {
+	c := s.acquire()
+	defer c.release()
+	if tagger, ok := c.fs.(spanTagger); ok {
+		return tagger.SpanTags()
+	}
+	return nil
+}
+
+func (s *Swappable) Ping(ctx context.Context) error {
+	c := s.acquire()
+	defer c.release()
+	return Ping(ctx, c.fs)
+}
+
+func (s *Swappable) MkdirAll(ctx context.Context, dirname string, perm os.FileMode) error {
+	c := s.acquire()
+	defer c.release()
+	return c.fs.MkdirAll(ctx, dirname, perm)
+}
+
+func (s *Swappable) IsExists(ctx context.Context, name string) (bool, error) {
+	c := s.acquire()
+	defer c.release()
+	return c.fs.IsExists(ctx, name)
+}
+
+func (s *Swappable) Stat(ctx context.Context, name string) (*FileInfo, error) {
+	c := s.acquire()
+	defer c.release()
+	return c.fs.Stat(ctx, name)
+}
+
+func (s *Swappable) List(ctx context.Context, dir string) ([]*FileInfo, error) {
+	c := s.acquire()
+	defer c.release()
+	return c.fs.List(ctx, dir)
+}
+
+func (s *Swappable) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	c := s.acquire()
+	defer c.release()
+	return c.fs.Walk(ctx, root, fn)
+}
+
+func (s *Swappable) CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (string, error) {
+	c := s.acquire()
+	defer c.release()
+	return c.fs.CreateAndWrite(ctx, name, reader)
+}
+
+func (s *Swappable) OpenForRead(ctx context.Context, name string) (io.ReadCloser, error) {
+	c := s.acquire()
+	reader, err := c.fs.OpenForRead(ctx, name)
+	if err != nil {
+		c.release()
+		return nil, err
+	}
+	return &swappableReader{ReadCloser: reader, client: c}, nil
+}
+
+func (s *Swappable) OpenRange(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	c := s.acquire()
+	reader, err := c.fs.OpenRange(ctx, name, offset, length)
+	if err != nil {
+		c.release()
+		return nil, err
+	}
+	return &swappableReader{ReadCloser: reader, client: c}, nil
+}
+
+func (s *Swappable) Remove(ctx context.Context, name string) error {
+	c := s.acquire()
+	defer c.release()
+	return c.fs.Remove(ctx, name)
+}
+
+func (s *Swappable) RemoveAll(ctx context.Context, name string) error {
+	c := s.acquire()
+	defer c.release()
+	return c.fs.RemoveAll(ctx, name)
+}
+
+func (s *Swappable) Rename(ctx context.Context, oldName string, newName string) error {
+	c := s.acquire()
+	defer c.release()
+	return c.fs.Rename(ctx, oldName, newName)
+}
+
+func (s *Swappable) RenameNoReplace(ctx context.Context, oldName string, newName string) error {
+	c := s.acquire()
+	defer c.release()
+	return RenameNoReplace(ctx, c.fs, oldName, newName)
+}
+
+// swappableReader releases the client when closed.
+type swappableReader struct {
+	io.ReadCloser
+	client *swappableClient
+	once   sync.Once
+}
+
+func (r *swappableReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.client.release)
+	return err
+}
@@ -0,0 +1,140 @@
+package fileio
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// closeCounter counts the calls of Close of the underlying FileIO.
+type closeCounter struct {
+	FileIO
+	closes int32
+}
+
+func (c *closeCounter) Close() error {
+	atomic.AddInt32(&c.closes, 1)
+	return c.FileIO.Close()
+}
+
+func (c *closeCounter) closed() int32 {
+	return atomic.LoadInt32(&c.closes)
+}
+
+func TestSwappableSwap(t *testing.T) {
+	ctx := testContext()
+	old := &closeCounter{FileIO: newTestMemory(t, 0)}
+	writeString(t, old, "/dir/a", "old")
+	s := NewSwappable(old)
+
+	// The reader opened from the previous client keeps it alive.
+	reader, err := s.OpenForRead(ctx, "/dir/a")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	current := &closeCounter{FileIO: newTestMemory(t, 0)}
+	writeString(t, current, "/dir/a", "new")
+	done := s.Swap(current)
+
+	if got := readString(t, s, "/dir/a"); got != "new" {
+		t.Fatalf("data = %q, want %q", got, "new")
+	}
+	select {
+	case err = <-done:
+		t.Fatalf("the previous client is closed in use: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if err = reader.Close(); err != nil {
+		t.Fatalf("close reader: %v", err)
+	}
+	// The reader closed twice releases the client once.
+	_ = reader.Close()
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatalf("close previous client: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the previous client is not closed after the reader closed")
+	}
+	if old.closed() != 1 || current.closed() != 0 {
+		t.Fatalf("closes = %d, %d, want 1, 0", old.closed(), current.closed())
+	}
+}
+
+func TestSwappableOpenFailed(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(s *Swappable) error
+	}{
+		{
+			name: "open for read",
+			open: func(s *Swappable) error {
+				_, err := s.OpenForRead(testContext(), "/dir/none")
+				return err
+			},
+		},
+		{
+			name: "open range",
+			open: func(s *Swappable) error {
+				_, err := s.OpenRange(testContext(), "/dir/none", 0, 1)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := &closeCounter{FileIO: newTestMemory(t, 0)}
+			s := NewSwappable(old)
+			if err := tt.open(s); err == nil {
+				t.Fatal("expected error to open a file not exists")
+			}
+			// The client is released when failed to open.
+			select {
+			case <-s.Swap(newTestMemory(t, 0)):
+			case <-time.After(time.Second):
+				t.Fatal("the previous client is not released")
+			}
+			if old.closed() != 1 {
+				t.Fatalf("closes = %d, want 1", old.closed())
+			}
+		})
+	}
+}
+
+func TestSwappableClose(t *testing.T) {
+	ctx := testContext()
+	fs := &closeCounter{FileIO: newTestMemory(t, 0)}
+	writeString(t, fs, "/dir/a", "a")
+	s := NewSwappable(fs)
+
+	reader, err := s.OpenForRead(ctx, "/dir/a")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	closed := make(chan error, 1)
+	go func() { closed <- s.Close() }()
+	select {
+	case <-closed:
+		t.Fatal("closed with the reader in use")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	_ = reader.Close()
+	select {
+	case err = <-closed:
+		if err != nil {
+			t.Fatalf("close: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("not closed after the reader closed")
+	}
+	if err = s.Close(); err != nil {
+		t.Fatalf("close again: %v", err)
+	}
+	if fs.closed() != 1 {
+		t.Fatalf("closes = %d, want 1", fs.closed())
+	}
+}
@@ -0,0 +1,219 @@
+package fileio
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// spanTagger is implemented by the FileIO that has the tags to set on all its spans,
+// such as the namenode of HDFS or the endpoint of S3.
+type spanTagger interface {
+	SpanTags() opentracing.Tags
+}
+
+// Traced is a FileIO that starts a span for each operation. The span is started only if
+// the context has a parent span, such as the span of gRPC request, so the background jobs
+// do not produce a trace for every storage call.
+//
+// The span of OpenForRead and OpenRange finishes when the reader is closed, and the bytes
+// read are tagged. The time spent to open is logged as an event of span.
+type Traced struct {
+	fs      FileIO
+	backend string
+	tracer  opentracing.Tracer
+}
+
+var _ FileIO = (*Traced)(nil)
+
+// NewTraced wraps fs to start spans by tracer with the backend tag.
+func NewTraced(fs FileIO, backend string, tracer opentracing.Tracer) *Traced {
+	return &Traced{fs: fs, backend: backend, tracer: tracer}
+}
+
+// start starts the child span of operation on name. Returns nil span if ctx has no parent.
+// The returned context carries the new span, so the backend can add tags to it.
+func (t *Traced) start(ctx context.Context, operation string, name string) (opentracing.Span, context.Context) {
+	parent := opentracing.SpanFromContext(ctx)
+	if parent == nil {
+		return nil, ctx
+	}
+	// The tags are got for each span, because the client may be swapped, see Swappable.
+	var tags opentracing.Tags
+	if tagger, ok := t.fs.(spanTagger); ok {
+		tags = tagger.SpanTags()
+	}
+	span := t.tracer.StartSpan("fileio."+operation, opentracing.ChildOf(parent.Context()), tags)
+	ext.Component.Set(span, "fileio")
+	span.SetTag("fileio.backend", t.backend)
+	span.SetTag("fileio.path", name)
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
+
+// finishSpan tags the err and finishes span. It is no-op if span is nil.
+func finishSpan(span opentracing.Span, err error) {
+	if span == nil {
+		return
+	}
+	switch {
+	case err == nil:
+	case os.IsNotExist(err):
+		span.SetTag("fileio.result", opResultNotFound)
+	case os.IsExist(err):
+		span.SetTag("fileio.result", opResultExists)
+	default:
+		ext.Error.Set(span, true)
+		span.LogFields(log.Error(err))
+	}
+	span.Finish()
+}
+
+func (t *Traced) Close() error {
+	return t.fs.Close()
+}
+
+func (t *Traced) Ping(ctx context.Context) (err error) {
+	span, ctx := t.start(ctx, "Ping", "")
+	defer func() { finishSpan(span, err) }()
+	return Ping(ctx, t.fs)
+}
+
+func (t *Traced) MkdirAll(ctx context.Context, dirname string, perm os.FileMode) (err error) {
+	span, ctx := t.start(ctx, "MkdirAll", dirname)
+	defer func() { finishSpan(span, err) }()
+	return t.fs.MkdirAll(ctx, dirname, perm)
+}
+
+func (t *Traced) IsExists(ctx context.Context, name string) (exists bool, err error) {
+	span, ctx := t.start(ctx, "IsExists", name)
+	defer func() { finishSpan(span, err) }()
+	return t.fs.IsExists(ctx, name)
+}
+
+func (t *Traced) Stat(ctx context.Context, name string) (info *FileInfo, err error) {
+	span, ctx := t.start(ctx, "Stat", name)
+	defer func() { finishSpan(span, err) }()
+	return t.fs.Stat(ctx, name)
+}
+
+func (t *Traced) List(ctx context.Context, dir string) (infos []*FileInfo, err error) {
+	span, ctx := t.start(ctx, "List", dir)
+	defer func() {
+		if span != nil {
+			span.SetTag("fileio.entries", len(infos))
+		}
+		finishSpan(span, err)
+	}()
+	return t.fs.List(ctx, dir)
+}
+
+// Walk is not traced as a whole, because its duration includes the time spent in fn.
+func (t *Traced) Walk(ctx context.Context, root string, fn WalkFunc) error {
+	return t.fs.Walk(ctx, root, fn)
+}
+
+func (t *Traced) CreateAndWrite(ctx context.Context, name string, reader io.ReadCloser) (eTag string, err error) {
+	span, ctx := t.start(ctx, "CreateAndWrite", name)
+	if span == nil {
+		return t.fs.CreateAndWrite(ctx, name, reader)
+	}
+	counter := &tracedReader{ReadCloser: reader}
+	defer func() {
+		span.SetTag("fileio.bytes", counter.n)
+		finishSpan(span, err)
+	}()
+	return t.fs.CreateAndWrite(ctx, name, counter)
+}
+
+func (t *Traced) OpenForRead(ctx context.Context, name string) (io.ReadCloser, error) {
+	span, ctx := t.start(ctx, "OpenForRead", name)
+	reader, err := t.fs.OpenForRead(ctx, name)
+	return t.traceReader(span, reader, err)
+}
+
+func (t *Traced) OpenRange(ctx context.Context, name string, offset int64, length int64) (io.ReadCloser, error) {
+	span, ctx := t.start(ctx, "OpenRange", name)
+	if span != nil {
+		span.SetTag("fileio.offset", offset)
+		span.SetTag("fileio.length", length)
+	}
+	reader, err := t.fs.OpenRange(ctx, name, offset, length)
+	return t.traceReader(span, reader, err)
+}
+
+// traceReader returns the reader that finishes span when closed.
+func (t *Traced) traceReader(span opentracing.Span, reader io.ReadCloser, err error) (io.ReadCloser, error) {
+	if span == nil {
+		return reader, err
+	}
+	if err != nil {
+		finishSpan(span, err)
+		return nil, err
+	}
+	span.LogFields(log.String("event", "opened"))
+	return &tracedReader{ReadCloser: reader, span: span}, nil
+}
+
+func (t *Traced) Remove(ctx context.Context, name string) (err error) {
+	span, ctx := t.start(ctx, "Remove", name)
+	defer func() { finishSpan(span, err) }()
+	return t.fs.Remove(ctx, name)
+}
+
+func (t *Traced) RemoveAll(ctx context.Context, name string) (err error) {
+	span, ctx := t.start(ctx, "RemoveAll", name)
+	defer func() { finishSpan(span, err) }()
+	return t.fs.RemoveAll(ctx, name)
+}
+
+func (t *Traced) Rename(ctx context.Context, oldName string, newName string) (err error) {
+	span, ctx := t.start(ctx, "Rename", oldName)
+	if span != nil {
+		span.SetTag("fileio.new_path", newName)
+	}
+	defer func() { finishSpan(span, err) }()
+	return t.fs.Rename(ctx, oldName, newName)
+}
+
+func (t *Traced) RenameNoReplace(ctx context.Context, oldName string, newName string) (err error) {
+	span, ctx := t.start(ctx, "RenameNoReplace", oldName)
+	if span != nil {
+		span.SetTag("fileio.new_path", newName)
+	}
+	defer func() { finishSpan(span, err) }()
+	return RenameNoReplace(ctx, t.fs, oldName, newName)
+}
+
+// tracedReader counts the bytes read, and finishes the span with the count when closed if span is not nil.
+type tracedReader struct {
+	io.ReadCloser
+	n    int64
+	span opentracing.Span
+	err  error
+	once sync.Once
+}
+
+func (r *tracedReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	r.n += int64(n)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+func (r *tracedReader) Close() error {
+	err := r.ReadCloser.Close()
+	if r.span != nil {
+		r.once.Do(func() {
+			r.span.SetTag("fileio.bytes", r.n)
+			finishSpan(r.span, r.err)
+		})
+	}
+	return err
+}
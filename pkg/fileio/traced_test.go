@@ -0,0 +1,161 @@
+package fileio
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestTracedNoParent(t *testing.T) {
+	tracer := mocktracer.New()
+	traced := NewTraced(newTestMemory(t, 0), "memory", tracer)
+	writeString(t, traced, "/dir/a", "hello")
+	readString(t, traced, "/dir/a")
+	if spans := tracer.FinishedSpans(); len(spans) != 0 {
+		t.Fatalf("%d spans started without parent", len(spans))
+	}
+}
+
+func TestTracedSpans(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		run       func(ctx context.Context, fs FileIO) error
+		tags      map[string]interface{}
+	}{
+		{
+			name:      "stat",
+			operation: "fileio.Stat",
+			run: func(ctx context.Context, fs FileIO) error {
+				_, err := fs.Stat(ctx, "/dir/a")
+				return err
+			},
+			tags: map[string]interface{}{"fileio.path": "/dir/a"},
+		},
+		{
+			name:      "not found",
+			operation: "fileio.Stat",
+			run: func(ctx context.Context, fs FileIO) error {
+				_, _ = fs.Stat(ctx, "/dir/none")
+				return nil
+			},
+			tags: map[string]interface{}{"fileio.path": "/dir/none", "fileio.result": opResultNotFound},
+		},
+		{
+			name:      "error",
+			operation: "fileio.Remove",
+			run: func(ctx context.Context, fs FileIO) error {
+				_ = fs.Remove(ctx, "/dir/a")
+				return nil
+			},
+			tags: map[string]interface{}{"fileio.path": "/dir/a", "error": true},
+		},
+		{
+			name:      "write",
+			operation: "fileio.CreateAndWrite",
+			run: func(ctx context.Context, fs FileIO) error {
+				_, err := fs.CreateAndWrite(ctx, "/dir/b", io.NopCloser(strings.NewReader("world!")))
+				return err
+			},
+			tags: map[string]interface{}{"fileio.path": "/dir/b", "fileio.bytes": int64(6)},
+		},
+		{
+			name:      "range read",
+			operation: "fileio.OpenRange",
+			run: func(ctx context.Context, fs FileIO) error {
+				reader, err := fs.OpenRange(ctx, "/dir/a", 1, 3)
+				if err != nil {
+					return err
+				}
+				_, _ = io.ReadAll(reader)
+				return reader.Close()
+			},
+			tags: map[string]interface{}{
+				"fileio.path": "/dir/a", "fileio.offset": int64(1), "fileio.length": int64(3), "fileio.bytes": int64(3),
+			},
+		},
+		{
+			name:      "rename",
+			operation: "fileio.Rename",
+			run: func(ctx context.Context, fs FileIO) error {
+				return fs.Rename(ctx, "/dir/a", "/dir/c")
+			},
+			tags: map[string]interface{}{"fileio.path": "/dir/a", "fileio.new_path": "/dir/c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMemory(t, 0)
+			writeString(t, m, "/dir/a", "hello")
+			tracer := mocktracer.New()
+			parent := tracer.StartSpan("parent")
+			ctx := opentracing.ContextWithSpan(testContext(), parent)
+
+			// The Remove fails with a storage error.
+			if err := tt.run(ctx, NewTraced(&removeFailedFS{FileIO: m}, "memory", tracer)); err != nil {
+				t.Fatalf("run: %v", err)
+			}
+			spans := tracer.FinishedSpans()
+			if len(spans) != 1 {
+				t.Fatalf("%d spans finished, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.OperationName != tt.operation || span.ParentID != parent.(*mocktracer.MockSpan).SpanContext.SpanID {
+				t.Fatalf("span %s with parent %d", span.OperationName, span.ParentID)
+			}
+			tags := span.Tags()
+			if tags["component"] != "fileio" || tags["fileio.backend"] != "memory" {
+				t.Fatalf("tags = %v", tags)
+			}
+			for key, want := range tt.tags {
+				if tags[key] != want {
+					t.Fatalf("tag %s = %v (%T), want %v", key, tags[key], tags[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestTracedReaderNotClosed(t *testing.T) {
+	m := newTestMemory(t, 0)
+	writeString(t, m, "/dir/a", "hello")
+	tracer := mocktracer.New()
+	ctx := opentracing.ContextWithSpan(testContext(), tracer.StartSpan("parent"))
+
+	reader, err := NewTraced(m, "memory", tracer).OpenForRead(ctx, "/dir/a")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// The span of read finishes when the reader closed, only once.
+	if n := len(tracer.FinishedSpans()); n != 0 {
+		t.Fatalf("%d spans finished before close", n)
+	}
+	_ = reader.Close()
+	_ = reader.Close()
+	if n := len(tracer.FinishedSpans()); n != 1 {
+		t.Fatalf("%d spans finished after close", n)
+	}
+}
+
+func TestTracedBackendTags(t *testing.T) {
+	svc := newFakeS3()
+	svc.objects["/dir/a"] = []byte("hello")
+	tracer := mocktracer.New()
+	ctx := opentracing.ContextWithSpan(testContext(), tracer.StartSpan("parent"))
+
+	traced := NewTraced(NewSwappable(newTestS3Client(svc, 1)), "s3", tracer)
+	if _, err := traced.Stat(ctx, "/dir/a"); err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	spans := tracer.FinishedSpans()
+	if len(spans) != 1 {
+		t.Fatalf("%d spans finished, want 1", len(spans))
+	}
+	if tags := spans[0].Tags(); tags["s3.endpoint"] != "s3.test" || tags["s3.bucket"] != "bucket" {
+		t.Fatalf("tags = %v", tags)
+	}
+}
@@ -0,0 +1,70 @@
+#!/usr/bin/env bash
+# Generate protobuf code for go
+
+if ! [[ "$0" =~ scripts/generate_go.sh ]]; then
+	echo "must be run from repository root"
+	exit 255
+fi
+
+MODULE="github.com/DataWorkbench/resourcemanager"
+GOPATH=$(go env GOPATH)
+
+if [ -z "${GOPATH}" ]; then
+    echo "Error: the environment variable GOPATH is not set, please set it before running"
+    exit 1
+fi
+
+for plugin in protoc protoc-gen-go protoc-gen-go-grpc protoc-gen-govalidator protoc-go-inject-tag; do
+  if ! type "${plugin}" > /dev/null 2>&1; then
+    echo "Error: the plugin <${plugin}> not install, please install it before running"
+    exit 1
+  fi
+done
+
+# check the plugin version, keep the same as gproto.
+if [[ $(protoc --version | cut -f2 -d' ') != "3.19.3" ]]; then
+  echo "Error: could not find protoc 3.19.3, is it installed in you PATH?"
+  exit 1
+fi
+
+if [[ $(protoc-gen-go --version 2>&1 | cut -f2 -d' ') != "v1.27.1" ]]; then
+  echo "Error: could not find protoc-gen-go v1.27.1, is it installed in you PATH?"
+  exit 1
+fi
+
+if [[ $(protoc-gen-go-grpc --version 2>&1 | cut -f2 -d' ') != "1.2.0" ]]; then
+  echo "Error: could not find protoc-gen-go-grpc 1.2.0, is it installed in you PATH?"
+  exit 1
+fi
+
+# The imported proto files of gproto and protoc-plugin are resolved from the module cache.
+GPROTO_DIR=$(go list -m -f '{{.Dir}}' github.com/DataWorkbench/gproto) || exit 1
+PLUGIN_DIR=$(go list -m -f '{{.Dir}}' github.com/yu31/protoc-plugin) || exit 1
+INCLUDE=$(mktemp -d)
+trap '/bin/rm -fr "${INCLUDE}"' EXIT
+mkdir -p "${INCLUDE}"/github.com/yu31
+ln -s "${PLUGIN_DIR}" "${INCLUDE}"/github.com/yu31/protoc-plugin
+
+for file in proto/*.proto; do
+  echo "generate Golang code with proto file ${file}"
+
+  protoc -I=. -I="${GPROTO_DIR}" -I="${INCLUDE}" --go_opt=module="${MODULE}" --go_out=. "${file}" || exit $?
+  protoc -I=. -I="${GPROTO_DIR}" -I="${INCLUDE}" --go-grpc_opt=module="${MODULE}" --go-grpc_out=. "${file}" || exit $?
+  protoc -I=. -I="${GPROTO_DIR}" -I="${INCLUDE}" --govalidator_opt=module="${MODULE}" --govalidator_out=. "${file}" || exit $?
+
+  # Inject tag to struct and remove comments.
+  name=${file##*/}
+  pbgo="./pkg/pbstoreio/${name%.*}.pb.go"
+  if grep "\@inject_tag" "${pbgo}" >/dev/null 2>&1; then
+    protoc-go-inject-tag -input="${pbgo}" || exit $?
+    if [ "$(uname -s)" == "Darwin" ] && ! sed --version >/dev/null 2>&1; then
+      sed -i "" '/\@inject_tag/d' "${pbgo}"
+    else
+      sed -i '/\@inject_tag/d' "${pbgo}"
+    fi
+  fi
+done
+
+go fmt ./pkg/pbstoreio/... >/dev/null 2>&1
+
+exit $?
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/controller"
+)
+
+// cancelWait is the time to wait the cancelled transfers to clean up and return.
+var cancelWait = 10 * time.Second
+
+// gracefulStopper is the gRPC server that drained, such as *grpcwrap.Server.
+type gracefulStopper interface {
+	GracefulStop()
+}
+
+// drain stops the gRPC server and HTTP gateway gracefully. The new transfers are rejected, and the
+// transfers in flight are logged until they finished or the drain timeout. Then the remaining are
+// cancelled and the gateway is closed at once. The grpcwrap server can not be stopped forcibly, the
+// cancelled gRPC transfers return without waiting for the blocked Send.
+func drain(lp *glog.Logger, cfg *config.Shutdown, rpcServer gracefulStopper, gateway *HTTPGatewayServer) {
+	controller.StartDrain()
+
+	stopped := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		rpcServer.GracefulStop()
+	}()
+	go func() {
+		defer wg.Done()
+		_ = gateway.Shutdown(context.Background())
+	}()
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	lp.Info().Msg("drain: waiting the transfers in flight to finish").
+		String("timeout", cfg.DrainTimeout.String()).Fire()
+	logTransfers(lp)
+
+	ticker := time.NewTicker(cfg.LogInterval)
+	defer ticker.Stop()
+	deadline := time.NewTimer(cfg.DrainTimeout)
+	defer deadline.Stop()
+	for {
+		select {
+		case <-stopped:
+			lp.Info().Msg("drain: all transfers finished").Fire()
+			return
+		case <-ticker.C:
+			logTransfers(lp)
+		case <-deadline.C:
+			logTransfers(lp)
+			n := controller.CancelTransfers()
+			lp.Warn().Msg("drain: timeout, cancel the transfers in flight").Int("count", n).Fire()
+			_ = gateway.Close()
+			select {
+			case <-stopped:
+				lp.Info().Msg("drain: the cancelled transfers returned").Fire()
+			case <-time.After(cancelWait):
+				lp.Error().Msg("drain: the server not stopped after cancelling transfers").
+					Int("count", len(controller.InFlightTransfers())).Fire()
+			}
+			return
+		}
+	}
+}
+
+// logTransfers logs the transfers in flight.
+func logTransfers(lp *glog.Logger) {
+	now := time.Now()
+	for _, t := range controller.InFlightTransfers() {
+		lp.Info().Msg("drain: transfer in flight").String("method", t.Method).String("spaceId", t.SpaceId).
+			String("fileId", t.FileId).String("version", t.Version).String("sessionId", t.SessionId).
+			String("elapsed", now.Sub(t.Started).Round(time.Millisecond).String()).Fire()
+	}
+}
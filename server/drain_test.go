@@ -0,0 +1,111 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+)
+
+// blockedStopper blocks GracefulStop until the channel closed.
+type blockedStopper chan struct{}
+
+func (s blockedStopper) GracefulStop() {
+	<-s
+}
+
+// newTestGateway returns the gateway with a request in flight, the returned channel is closed
+// when the request is aborted by closing the connection.
+func newTestGateway(t *testing.T, lp *glog.Logger) (*HTTPGatewayServer, <-chan struct{}) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	started, aborted := make(chan struct{}), make(chan struct{})
+	gateway := &HTTPGatewayServer{
+		lp:  lp,
+		cfg: &config.HTTPGateway{Enabled: true, Address: ln.Addr().String()},
+		h: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-r.Context().Done()
+			close(aborted)
+		})},
+	}
+	go func() { _ = gateway.h.Serve(ln) }()
+	go func() {
+		if resp, err := http.Get("http://" + ln.Addr().String()); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the request is not started")
+	}
+	return gateway, aborted
+}
+
+func TestDrain(t *testing.T) {
+	lp := glog.NewDefault().WithLevel(glog.FatalLevel)
+	cfg := &config.Shutdown{DrainTimeout: 50 * time.Millisecond, LogInterval: 10 * time.Millisecond}
+	defer func(wait time.Duration) { cancelWait = wait }(cancelWait)
+	cancelWait = 300 * time.Millisecond
+
+	tests := []struct {
+		name string
+		// setup returns the gRPC server and gateway drained.
+		setup func(t *testing.T) (gracefulStopper, *HTTPGatewayServer)
+		min   time.Duration
+		max   time.Duration
+	}{
+		{
+			name: "stopped before deadline",
+			setup: func(t *testing.T) (gracefulStopper, *HTTPGatewayServer) {
+				s := make(blockedStopper)
+				close(s)
+				return s, nil
+			},
+			max: cfg.DrainTimeout,
+		},
+		{
+			// The gateway is closed at the deadline, the aborted request returns and the gRPC
+			// server stops, so drain returns without waiting the cancelWait.
+			name: "stopped after closing gateway",
+			setup: func(t *testing.T) (gracefulStopper, *HTTPGatewayServer) {
+				gateway, aborted := newTestGateway(t, lp)
+				s := make(blockedStopper)
+				go func() {
+					<-aborted
+					close(s)
+				}()
+				return s, gateway
+			},
+			min: cfg.DrainTimeout,
+			max: cfg.DrainTimeout + cancelWait,
+		},
+		{
+			name: "not stopped after deadline",
+			setup: func(t *testing.T) (gracefulStopper, *HTTPGatewayServer) {
+				s := make(blockedStopper)
+				t.Cleanup(func() { close(s) })
+				return s, nil
+			},
+			min: cfg.DrainTimeout + cancelWait,
+			max: cfg.DrainTimeout + cancelWait + time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rpcServer, gateway := tt.setup(t)
+			start := time.Now()
+			drain(lp, cfg, rpcServer, gateway)
+			if elapsed := time.Since(start); elapsed < tt.min || elapsed >= tt.max {
+				t.Fatalf("drain returned after %s, want in [%s, %s)", elapsed, tt.min, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthServer serves the grpc.health.v1 service whose status reflects the connectivity of
+// storage backends. All backends are probed periodically, the status flips to NOT_SERVING
+// when any backend failed consecutively for the threshold, and back to SERVING when all
+// backends are reachable again. The status is NOT_SERVING before the first probe finished
+// and after Shutdown. It is served on its own address, because the health service that
+// grpcwrap registers on the main port is always SERVING and can not be replaced.
+type HealthServer struct {
+	lp       *glog.Logger
+	cfg      *config.Health
+	storages *fileio.Registry
+	health   *health.Server
+	gRPC     *grpc.Server
+
+	failures map[string]int // The number of consecutive failures by backend name.
+	serving  bool
+}
+
+// NewHealthServer creates a HealthServer that probes the backends in storages.
+func NewHealthServer(ctx context.Context, cfg *config.Health, storages *fileio.Registry) *HealthServer {
+	s := &HealthServer{
+		lp:       glog.FromContext(ctx),
+		cfg:      cfg,
+		storages: storages,
+		health:   health.NewServer(),
+		gRPC:     grpc.NewServer(),
+		failures: make(map[string]int),
+	}
+	s.setServingStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	grpc_health_v1.RegisterHealthServer(s.gRPC, s.health)
+	return s
+}
+
+// setServingStatus sets the status of server and the StoreIO service.
+func (s *HealthServer) setServingStatus(status grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	s.health.SetServingStatus("", status)
+	s.health.SetServingStatus(pbstoreio.StoreIO_ServiceDesc.ServiceName, status)
+}
+
+// Run probes the storage backends at interval until ctx is done.
+func (s *HealthServer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		s.probe(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe pings all backends concurrently and updates the serving status.
+func (s *HealthServer) probe(ctx context.Context) {
+	names := s.storages.Names()
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			errs[i] = s.ping(ctx, name)
+		}(i, name)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	serving := true
+	for i, name := range names {
+		if errs[i] == nil {
+			if s.failures[name] > 0 {
+				s.lp.Info().Msg("health: storage is reachable again").String("storage", name).Fire()
+			}
+			s.failures[name] = 0
+			continue
+		}
+		s.failures[name]++
+		s.lp.Warn().Msg("health: probe storage failed").String("storage", name).
+			Int("failures", s.failures[name]).Error("error", errs[i]).Fire()
+		if s.failures[name] >= s.cfg.FailureThreshold {
+			serving = false
+		}
+	}
+	if serving == s.serving {
+		return
+	}
+	s.serving = serving
+	if serving {
+		s.lp.Info().Msg("health: set status to SERVING").Fire()
+		s.setServingStatus(grpc_health_v1.HealthCheckResponse_SERVING)
+	} else {
+		s.lp.Error().Msg("health: set status to NOT_SERVING").Fire()
+		s.setServingStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// ping checks the backend with timeout. The client of some storage, such as HDFS, does not
+// accept context, so the ping is abandoned rather than cancelled when timeout.
+func (s *HealthServer) ping(ctx context.Context, name string) error {
+	fs, ok := s.storages.Get(name)
+	if !ok {
+		return fmt.Errorf("storage %s is not registered", name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.ProbeTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fileio.Ping(ctx, fs)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ListenAndServe listens on the address in config and serves the health service.
+func (s *HealthServer) ListenAndServe() error {
+	s.lp.Info().String("health server: start listening", s.cfg.Address).Fire()
+
+	lis, err := net.Listen("tcp", s.cfg.Address)
+	if err != nil {
+		s.lp.Error().Error("health server: create listener error", err).Fire()
+		return err
+	}
+	if err = s.gRPC.Serve(lis); err != nil {
+		s.lp.Error().Error("health server: serve error", err).Fire()
+	}
+	return err
+}
+
+// Shutdown sets the status to NOT_SERVING permanently, the later probes are ignored.
+// It is called before the server stops so the new requests are routed to other pods.
+func (s *HealthServer) Shutdown() {
+	if s == nil {
+		return
+	}
+	s.lp.Info().Msg("health: set status to NOT_SERVING for shutdown").Fire()
+	s.health.Shutdown()
+}
+
+// Stop stops serving the health service.
+func (s *HealthServer) Stop() {
+	if s == nil {
+		return
+	}
+	s.gRPC.Stop()
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/pkg/fileio"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testContext returns a context with the logger that only exports the fatal records.
+func testContext() context.Context {
+	return glog.WithContext(context.Background(), glog.NewDefault().WithLevel(glog.FatalLevel))
+}
+
+// pingFS is the storage that Ping returns err, or blocks until the context done if block.
+type pingFS struct {
+	fileio.FileIO
+
+	mu    sync.Mutex
+	err   error
+	block bool
+}
+
+func (fs *pingFS) set(err error, block bool) {
+	fs.mu.Lock()
+	fs.err, fs.block = err, block
+	fs.mu.Unlock()
+}
+
+func (fs *pingFS) Ping(ctx context.Context) error {
+	fs.mu.Lock()
+	err, block := fs.err, fs.block
+	fs.mu.Unlock()
+	if block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return err
+}
+
+func TestHealthServerProbe(t *testing.T) {
+	ctx := testContext()
+	registry := fileio.NewRegistry()
+	backends := map[string]*pingFS{"default": {}, "archive": {}}
+	for name, fs := range backends {
+		mem, err := fileio.NewMemory(ctx, 0)
+		if err != nil {
+			t.Fatalf("new memory: %v", err)
+		}
+		fs.FileIO = mem
+		if err = registry.Register(name, fs); err != nil {
+			t.Fatalf("register: %v", err)
+		}
+	}
+	cfg := &config.Health{ProbeInterval: time.Hour, ProbeTimeout: 10 * time.Millisecond, FailureThreshold: 2}
+	s := NewHealthServer(ctx, cfg, registry)
+	errProbe := errors.New("unreachable")
+
+	steps := []struct {
+		name    string
+		backend string
+		err     error
+		block   bool
+		want    grpc_health_v1.HealthCheckResponse_ServingStatus
+	}{
+		{name: "all reachable", want: grpc_health_v1.HealthCheckResponse_SERVING},
+		{name: "failed once", backend: "archive", err: errProbe, want: grpc_health_v1.HealthCheckResponse_SERVING},
+		{name: "failed to threshold", backend: "archive", err: errProbe, want: grpc_health_v1.HealthCheckResponse_NOT_SERVING},
+		{name: "reachable again", want: grpc_health_v1.HealthCheckResponse_SERVING},
+		{name: "timeout once", backend: "default", block: true, want: grpc_health_v1.HealthCheckResponse_SERVING},
+		{name: "timeout to threshold", backend: "default", block: true, want: grpc_health_v1.HealthCheckResponse_NOT_SERVING},
+		{name: "recovered", want: grpc_health_v1.HealthCheckResponse_SERVING},
+	}
+	if got := healthStatus(t, s, ""); got != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("status before probe = %s", got)
+	}
+	for _, step := range steps {
+		for name, fs := range backends {
+			if name == step.backend {
+				fs.set(step.err, step.block)
+			} else {
+				fs.set(nil, false)
+			}
+		}
+		s.probe(ctx)
+		for _, service := range []string{"", pbstoreio.StoreIO_ServiceDesc.ServiceName} {
+			if got := healthStatus(t, s, service); got != step.want {
+				t.Fatalf("%s: status of <%s> = %s, want %s", step.name, service, got, step.want)
+			}
+		}
+	}
+
+	// The status is NOT_SERVING after shutdown even if all reachable.
+	s.Shutdown()
+	s.probe(ctx)
+	if got := healthStatus(t, s, ""); got != grpc_health_v1.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("status after shutdown = %s", got)
+	}
+}
+
+func healthStatus(t *testing.T, s *HealthServer, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	reply, err := s.health.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("check: %v", err)
+	}
+	return reply.Status
+}
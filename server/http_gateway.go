@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/controller"
+)
+
+// HTTPGatewayServer serves the controller.HTTPGateway. The requests are not bound to the
+// context of server, so the transfers in flight are drained rather than cancelled on shutdown.
+type HTTPGatewayServer struct {
+	lp  *glog.Logger
+	cfg *config.HTTPGateway
+	h   *http.Server
+}
+
+// NewHTTPGatewayServer returns nil if the gateway is disabled.
+func NewHTTPGatewayServer(lp *glog.Logger, cfg *config.HTTPGateway) *HTTPGatewayServer {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &HTTPGatewayServer{
+		lp:  lp,
+		cfg: cfg,
+		h: &http.Server{
+			Addr:              cfg.Address,
+			Handler:           controller.NewHTTPGateway(lp, cfg),
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+		},
+	}
+}
+
+func (s *HTTPGatewayServer) ListenAndServe() (err error) {
+	if s == nil {
+		return
+	}
+	s.lp.Info().String("http gateway: server listening", s.cfg.Address).Fire()
+
+	err = s.h.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		s.lp.Error().Error("http gateway: listen and serve error", err).Fire()
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting new connections and waits the active requests to finish.
+func (s *HTTPGatewayServer) Shutdown(ctx context.Context) (err error) {
+	if s == nil {
+		return
+	}
+	s.lp.Info().Msg("http gateway: waiting for server shutdown").Fire()
+	if err = s.h.Shutdown(ctx); err != nil {
+		s.lp.Error().Error("http gateway: shutdown server error", err).Fire()
+		return
+	}
+	s.lp.Info().Msg("http gateway: shutdown server done").Fire()
+	return
+}
+
+// Close closes all connections immediately.
+func (s *HTTPGatewayServer) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.h.Close()
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"sync/atomic"
+
+	"github.com/DataWorkbench/common/utils/logutil"
+	"github.com/DataWorkbench/glog"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// levelExporter drops the records below the level that can be changed at runtime.
+//
+// The level of glog.Logger is read without lock and copied into the cloned loggers, so changing
+// it after the server started is a data race and does not reach the clones. Instead the root
+// logger accepts all levels, and the level is applied here, that is shared by all clones.
+type levelExporter struct {
+	glog.Exporter
+	level int32
+}
+
+func (e *levelExporter) Export(record *glog.Record) error {
+	if record.Level() < glog.Level(atomic.LoadInt32(&e.level)) {
+		return nil
+	}
+	return e.Exporter.Export(record)
+}
+
+// SetLevel changes the minimum level to export, it is safe for concurrent use.
+func (e *levelExporter) SetLevel(level glog.Level) {
+	atomic.StoreInt32(&e.level, int32(level))
+}
+
+// newRootLogger creates the root logger same as logutil.New, but the level can be changed
+// by the returned levelExporter.
+func newRootLogger(cfg *logutil.Config) (*glog.Logger, *levelExporter, error) {
+	// Check the config and create the logger with the same defaults.
+	lp, err := logutil.New(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	exporter := &levelExporter{Exporter: glog.DefaultExporter, level: int32(cfg.Level)}
+	if cfg.Output == "file" {
+		exporter.Exporter = glog.StandardExporter(&lumberjack.Logger{
+			Filename:   cfg.File.Path,
+			MaxSize:    cfg.File.MaxSize,
+			MaxAge:     cfg.File.MaxAge,
+			MaxBackups: cfg.File.MaxBackups,
+			LocalTime:  true,
+			Compress:   cfg.File.Compress,
+		})
+	}
+	lp.WithLevel(glog.DebugLevel).WithExporter(exporter)
+	return lp, exporter, nil
+}
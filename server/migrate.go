@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/DataWorkbench/common/utils/logutil"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/controller"
+	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
+)
+
+// Migrate copies the workspaces from source storage backend to target and prints the progress.
+// It can be interrupted by signal and resumed by running again, the copied files are skipped.
+func Migrate(source, target string, spaceIds []string, concurrency int) (err error) {
+	var cfg *config.Config
+	if cfg, err = config.Load(); err != nil {
+		return
+	}
+
+	var lp *glog.Logger
+	if lp, err = logutil.New(cfg.LogConfig); err != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(glog.WithContext(context.Background(), lp))
+	defer func() {
+		cancel()
+		_ = options.Close()
+		_ = lp.Close()
+	}()
+
+	if err = options.Init(ctx, cfg); err != nil {
+		return
+	}
+
+	req := &pbstoreio.StartMigrationRequest{
+		Source:      source,
+		Target:      target,
+		SpaceIds:    spaceIds,
+		Concurrency: int32(concurrency),
+	}
+	if err = req.Validate(); err != nil {
+		return
+	}
+	migrator, err := controller.NewMigrator(source, target, spaceIds, concurrency)
+	if err != nil {
+		return
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigChan
+		lp.Info().String("receive system signal", sig.String()).Fire()
+		cancel()
+	}()
+
+	err = migrator.Run(ctx)
+	b, _ := json.MarshalIndent(migrator.Progress(), "", "  ")
+	fmt.Println(string(b))
+	return
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/controller"
+	"github.com/DataWorkbench/resourcemanager/options"
+)
+
+// storageClientFields are the fields of storage that can be applied by swapping in a new
+// client. The fields that point to other data, such as background and bucket, need a restart.
+var storageClientFields = map[string]bool{
+	"hadoop_conf_dir":         true,
+	"hadoop_user":             true,
+	"hadoop_client_pool_size": true,
+	"kerberos":                true,
+	"s3":                      true,
+}
+
+// reloader reloads the configuration on SIGHUP or when the config file modified, and applies
+// the changes that are safe to apply live. The other changes are reported as restart required.
+type reloader struct {
+	lp       *glog.Logger
+	levels   *levelExporter       // The level of lp and all its clones.
+	scrubber *controller.Scrubber // nil if the scrubber is disabled.
+
+	mu      sync.Mutex
+	initial *config.Config // The config that the server started with.
+	current *config.Config // The config that applied last time.
+}
+
+func newReloader(lp *glog.Logger, levels *levelExporter, cfg *config.Config, scrubber *controller.Scrubber) *reloader {
+	return &reloader{lp: lp, levels: levels, scrubber: scrubber, initial: cfg, current: cfg}
+}
+
+// watch reloads the configuration when the config file modified until ctx is done.
+func (r *reloader) watch(ctx context.Context, interval time.Duration) {
+	if config.FilePath == "" {
+		return
+	}
+	last, err := os.Stat(config.FilePath)
+	if err != nil {
+		r.lp.Warn().Msg("reload: stat config file failed").Error("error", err).Fire()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(config.FilePath)
+		if err != nil {
+			// The file may be being replaced, check again later.
+			continue
+		}
+		if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			continue
+		}
+		last = info
+		r.reload(ctx, "config file modified")
+	}
+}
+
+// reload loads the configuration and applies the changes. The current configuration is
+// kept if the new one is invalid.
+func (r *reloader) reload(ctx context.Context, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lp.Info().Msg("reload: reloading configuration").String("reason", reason).Fire()
+	cfg, err := config.Load()
+	if err != nil {
+		r.lp.Error().Msg("reload: load configuration failed, keep the current").Error("error", err).Fire()
+		return
+	}
+
+	var applied, failed []string
+	swaps := make(map[string][]string) // The changed fields by backend name.
+	for _, path := range config.Diff(r.current, cfg) {
+		if !isLiveField(path) {
+			continue
+		}
+		if name, _, ok := storageField(path); ok {
+			// Swap the client once even if multiple fields of the backend changed.
+			swaps[name] = append(swaps[name], path)
+			continue
+		}
+		if err = r.apply(cfg, path); err != nil {
+			r.lp.Error().Msg("reload: apply change failed").String("field", path).Error("error", err).Fire()
+			failed = append(failed, path)
+			continue
+		}
+		applied = append(applied, path)
+	}
+	for _, name := range cfg.StorageNames() {
+		if len(swaps[name]) == 0 {
+			continue
+		}
+		if err = options.ReloadStorage(ctx, name, cfg.GetStorage(name)); err != nil {
+			r.lp.Error().Msg("reload: swap storage client failed").String("storage", name).Error("error", err).Fire()
+			failed = append(failed, swaps[name]...)
+			continue
+		}
+		applied = append(applied, swaps[name]...)
+		r.lp.Info().Msg("reload: storage client swapped").String("storage", name).Fire()
+	}
+
+	var restart []string
+	for _, path := range config.Diff(r.initial, cfg) {
+		if !isLiveField(path) {
+			restart = append(restart, path)
+		}
+	}
+	// Keep the current if any change failed, so the changes are applied again in next reload.
+	if len(failed) == 0 {
+		r.current = cfg
+	}
+
+	r.lp.Info().Msg("reload: configuration reloaded").Strings("applied", applied).Strings("failed", failed).Fire()
+	if len(restart) > 0 {
+		r.lp.Warn().Msg("reload: the changes need a restart to take effect").Strings("fields", restart).Fire()
+	}
+}
+
+// apply applies the change of the field in path that not belongs to storage backends.
+func (r *reloader) apply(cfg *config.Config, path string) error {
+	switch path {
+	case "log.level":
+		r.levels.SetLevel(glog.Level(cfg.LogConfig.Level))
+	case "read_cache.capacity":
+		return options.SetReadCacheCapacity(cfg.ReadCache.Capacity)
+	case "scrubber.rate_limit":
+		if r.scrubber != nil {
+			r.scrubber.SetRateLimit(cfg.Scrubber.RateLimit)
+		}
+	}
+	return nil
+}
+
+// isLiveField reports whether the change of field in path can be applied without restart.
+func isLiveField(path string) bool {
+	switch path {
+	case "log.level", "read_cache.capacity", "scrubber.rate_limit":
+		return true
+	}
+	_, field, ok := storageField(path)
+	if !ok {
+		return false
+	}
+	top := strings.SplitN(field, ".", 2)[0]
+	return storageClientFields[top] && field != "s3.bucket"
+}
+
+// storageField splits the path of storage field into the backend name and the field path.
+func storageField(path string) (name string, field string, ok bool) {
+	if strings.HasPrefix(path, "storage.") {
+		return config.DefaultStorageName, strings.TrimPrefix(path, "storage."), true
+	}
+	if strings.HasPrefix(path, "storages.") {
+		parts := strings.SplitN(path, ".", 3)
+		if len(parts) == 3 {
+			return parts[1], parts[2], true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/DataWorkbench/common/utils/logutil"
+	"github.com/DataWorkbench/glog"
+	"github.com/DataWorkbench/resourcemanager/config"
+	"github.com/DataWorkbench/resourcemanager/controller"
+	"github.com/DataWorkbench/resourcemanager/options"
+)
+
+// Scrub runs a scrub pass to verify all stored resources and prints the result.
+// It resumes from the last checkpoint, and saves the progress when interrupted by signal.
+func Scrub(quarantine bool) (err error) {
+	var cfg *config.Config
+	if cfg, err = config.Load(); err != nil {
+		return
+	}
+	if quarantine {
+		cfg.Scrubber.Quarantine = true
+	}
+
+	var lp *glog.Logger
+	if lp, err = logutil.New(cfg.LogConfig); err != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(glog.WithContext(context.Background(), lp))
+	defer func() {
+		cancel()
+		_ = options.Close()
+		_ = lp.Close()
+	}()
+
+	if err = options.Init(ctx, cfg); err != nil {
+		return
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigChan
+		lp.Info().String("receive system signal", sig.String()).Fire()
+		cancel()
+	}()
+
+	result, err := controller.NewScrubber(cfg.Scrubber).RunOnce(ctx)
+	if result != nil {
+		b, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(b))
+	}
+	if err != nil {
+		return
+	}
+	if result.Mismatched > 0 {
+		return fmt.Errorf("found %d mismatched files", result.Mismatched)
+	}
+	return
+}
@@ -13,12 +13,11 @@ import (
 	"github.com/DataWorkbench/common/gtrace"
 	"github.com/DataWorkbench/common/metrics"
 	"github.com/DataWorkbench/common/utils/buildinfo"
-	"github.com/DataWorkbench/common/utils/logutil"
 	"github.com/DataWorkbench/glog"
-	"github.com/DataWorkbench/gproto/xgo/service/pbsvcstoreio"
 	"github.com/DataWorkbench/resourcemanager/config"
 	"github.com/DataWorkbench/resourcemanager/controller"
 	"github.com/DataWorkbench/resourcemanager/options"
+	"github.com/DataWorkbench/resourcemanager/pkg/pbstoreio"
 )
 
 // Start for start the http server
@@ -33,21 +32,30 @@ func Start() (err error) {
 
 	var (
 		lp           *glog.Logger
+		levels       *levelExporter
 		rpcServer    *grpcwrap.Server
 		metricServer *metrics.Server
+		healthServer *HealthServer
+		gateway      *HTTPGatewayServer
 		tracer       gtrace.Tracer
 		tracerCloser io.Closer
 	)
 
 	// init root logger
-	if lp, err = logutil.New(cfg.LogConfig); err != nil {
+	if lp, levels, err = newRootLogger(cfg.LogConfig); err != nil {
 		return
 	}
 	// Init context.Context.
-	ctx := glog.WithContext(context.Background(), lp)
+	ctx, cancel := context.WithCancel(glog.WithContext(context.Background(), lp))
 
 	defer func() {
-		rpcServer.GracefulStop()
+		cancel()
+		// Report NOT_SERVING first, so the new streams go to the other servers during the drain.
+		healthServer.Shutdown()
+		if rpcServer != nil {
+			drain(lp, cfg.Shutdown, rpcServer, gateway)
+		}
+		healthServer.Stop()
 		_ = metricServer.Shutdown(ctx)
 
 		_ = options.Close()
@@ -68,6 +76,37 @@ func Start() (err error) {
 		return
 	}
 
+	healthServer = NewHealthServer(ctx, cfg.Health, options.Storages)
+	go healthServer.Run(ctx)
+
+	// Remove the stale staging files in background, it may take a long time.
+	go controller.SweepStagingFiles(ctx, cfg.Storage.StagingExpire)
+	go controller.RunUploadSessionSweeper(ctx, cfg.Storage.UploadSessionTTL)
+	if cfg.Trash.Enabled {
+		go controller.RunTrashPurger(ctx, cfg.Trash.PurgeInterval)
+	}
+	var scrubber *controller.Scrubber
+	if cfg.Scrubber.Enabled {
+		scrubber = controller.NewScrubber(cfg.Scrubber)
+		go scrubber.Run(ctx)
+	}
+
+	// Reload the configuration on SIGHUP or when config file modified.
+	reloader := newReloader(lp, levels, cfg, scrubber)
+	go reloader.watch(ctx, cfg.Reload.WatchInterval)
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hupChan:
+				reloader.reload(ctx, "receive SIGHUP")
+			}
+		}
+	}()
+
 	// init prometheus server
 	metricServer, err = metrics.NewServer(ctx, cfg.MetricsServer)
 	if err != nil {
@@ -79,7 +118,10 @@ func Start() (err error) {
 		return
 	}
 
-	rpcServer.RegisterService(&pbsvcstoreio.StoreIO_ServiceDesc, &controller.StoreIo{})
+	rpcServer.RegisterService(&pbstoreio.StoreIO_ServiceDesc, &controller.StoreIo{})
+
+	// The optional HTTP gateway for the clients that can't use gRPC.
+	gateway = NewHTTPGatewayServer(lp, cfg.HTTPGateway)
 
 	// handle signal
 	sigGroup := []os.Signal{syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM}
@@ -94,6 +136,16 @@ func Start() (err error) {
 		blockChan <- struct{}{}
 	}()
 
+	go func() {
+		// The readiness probe fails if the health server failed to start.
+		_ = healthServer.ListenAndServe()
+	}()
+
+	go func() {
+		// The gRPC server keeps serving if the gateway failed to start.
+		_ = gateway.ListenAndServe()
+	}()
+
 	go func() {
 		// Ignore metrics server error.
 		_ = metricServer.ListenAndServe()